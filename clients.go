@@ -0,0 +1,169 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Client represents a client.
+type Client struct {
+	Wid      string `json:"workspaceId"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Address  string `json:"address"`
+	Note     string `json:"note"`
+	Archived bool   `json:"archived"`
+}
+
+// ClientFilter narrows down the clients returned by ListClients. Zero fields
+// are not used for filtering.
+type ClientFilter struct {
+	// Name matches clients whose name contains it.
+	Name     string
+	Archived *bool
+	// SortColumn is "NAME".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// PageSize is the number of clients fetched per request.
+	PageSize int
+}
+
+func (filter ClientFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	setBoolParam(params, "archived", filter.Archived)
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	return params
+}
+
+// ClientRequest represents a request creating a client.
+type ClientRequest struct {
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Address string `json:"address,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// UpdateClientRequest represents a request changing a client. Fields left
+// empty or nil are not changed.
+type UpdateClientRequest struct {
+	Name     string  `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Address  *string `json:"address,omitempty"`
+	Note     *string `json:"note,omitempty"`
+	Archived *bool   `json:"archived,omitempty"`
+}
+
+// GetClients returns all clients of a workspace matching the filter.
+func (session *Session) GetClients(workspaceID string, filter ClientFilter) ([]Client, error) {
+	return session.GetClientsContext(context.Background(), workspaceID, filter)
+}
+
+// GetClientsContext is like GetClients but uses the given context for the
+// requests.
+func (session *Session) GetClientsContext(ctx context.Context, workspaceID string, filter ClientFilter) ([]Client, error) {
+	dlog.Printf("Getting clients for workspace %s", workspaceID)
+	return session.ListClientsContext(ctx, workspaceID, filter).Collect(0)
+}
+
+// ListClients returns an iterator over the clients of a workspace matching the
+// filter.
+func (session *Session) ListClients(workspaceID string, filter ClientFilter) *ClientIterator {
+	return session.ListClientsContext(context.Background(), workspaceID, filter)
+}
+
+// ListClientsContext is like ListClients but uses the given context for the
+// requests.
+func (session *Session) ListClientsContext(ctx context.Context, workspaceID string, filter ClientFilter) *ClientIterator {
+	path := fmt.Sprintf("/workspaces/%s/clients", workspaceID)
+	return &ClientIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeClients)}
+}
+
+// CreateClient adds a new client to a workspace.
+func (session *Session) CreateClient(workspaceID string, req ClientRequest) (Client, error) {
+	return session.CreateClientContext(context.Background(), workspaceID, req)
+}
+
+// CreateClientContext is like CreateClient but uses the given context for the
+// request.
+func (session *Session) CreateClientContext(ctx context.Context, workspaceID string, req ClientRequest) (Client, error) {
+	dlog.Printf("Creating client %s", req.Name)
+	path := fmt.Sprintf("/workspaces/%s/clients", workspaceID)
+	respData, err := session.post(ctx, session.apiURL(), path, req)
+	return requestClient(respData, err)
+}
+
+// UpdateClient changes information about an existing client.
+func (session *Session) UpdateClient(workspaceID, clientID string, req UpdateClientRequest) (Client, error) {
+	return session.UpdateClientContext(context.Background(), workspaceID, clientID, req)
+}
+
+// UpdateClientContext is like UpdateClient but uses the given context for the
+// request.
+func (session *Session) UpdateClientContext(ctx context.Context, workspaceID, clientID string, req UpdateClientRequest) (Client, error) {
+	dlog.Printf("Updating client %v", clientID)
+	path := fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID)
+	respData, err := session.put(ctx, session.apiURL(), path, req)
+	return requestClient(respData, err)
+}
+
+// ArchiveClient archives a client.
+func (session *Session) ArchiveClient(workspaceID, clientID string) (Client, error) {
+	return session.ArchiveClientContext(context.Background(), workspaceID, clientID)
+}
+
+// ArchiveClientContext is like ArchiveClient but uses the given context for
+// the request.
+func (session *Session) ArchiveClientContext(ctx context.Context, workspaceID, clientID string) (Client, error) {
+	return session.UpdateClientContext(ctx, workspaceID, clientID, UpdateClientRequest{Archived: Bool(true)})
+}
+
+// DeleteClient deletes a client. Clockify only deletes archived clients, so
+// the client is archived first.
+func (session *Session) DeleteClient(workspaceID, clientID string) (Client, error) {
+	return session.DeleteClientContext(context.Background(), workspaceID, clientID)
+}
+
+// DeleteClientContext is like DeleteClient but uses the given context for the
+// requests.
+func (session *Session) DeleteClientContext(ctx context.Context, workspaceID, clientID string) (Client, error) {
+	if _, err := session.ArchiveClientContext(ctx, workspaceID, clientID); err != nil {
+		return Client{}, err
+	}
+
+	dlog.Printf("Deleting client %v", clientID)
+	path := fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestClient(respData, err)
+}
+
+func decodeClients(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]Client{})
+}
+
+func requestClient(data []byte, err error) (Client, error) {
+	if err != nil {
+		return Client{}, err
+	}
+
+	var client Client
+	err = json.Unmarshal(data, &client)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, client)
+	if err != nil {
+		return Client{}, err
+	}
+
+	return client, nil
+}
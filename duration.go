@@ -0,0 +1,195 @@
+package clockify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration encoded in JSON as an ISO-8601 duration such as
+// "PT1H30M15S", which is how Clockify represents durations. A JSON null, as
+// sent for running time entries, decodes to zero.
+type Duration time.Duration
+
+// Duration returns d as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns d in ISO-8601 form. Durations are expressed in hours,
+// minutes and seconds only, e.g. "PT26H5M0.5S".
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	n := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		n = -n
+	}
+	b.WriteString("PT")
+
+	if hours := n / uint64(time.Hour); hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10))
+		b.WriteByte('H')
+	}
+	if minutes := n % uint64(time.Hour) / uint64(time.Minute); minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10))
+		b.WriteByte('M')
+	}
+	if rest := n % uint64(time.Minute); rest > 0 {
+		b.WriteString(strconv.FormatUint(rest/uint64(time.Second), 10))
+		if nanos := rest % uint64(time.Second); nanos > 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		b.WriteByte('S')
+	}
+
+	return b.String()
+}
+
+// MarshalJSON encodes d as an ISO-8601 string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes an ISO-8601 string or null into d.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*d = 0
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// durationUnits maps the designators of an ISO-8601 duration to their length,
+// separately for the date and the time part. Years and months are left out
+// since their length varies.
+var durationUnits = [2]map[byte]time.Duration{
+	{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour},
+	{'H': time.Hour, 'M': time.Minute, 'S': time.Second},
+}
+
+// durationOrder lists the designators of each part of an ISO-8601 duration in
+// the order they must appear in.
+var durationOrder = [2]string{"WD", "HMS"}
+
+// ParseDuration parses an ISO-8601 duration such as "PT1H30M15S" or
+// "P1DT0.5S". An empty string parses as zero. Designators may not be repeated
+// and must appear in decreasing order of size, and only the last value may
+// have a fraction.
+func ParseDuration(s string) (Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	invalid := func() (Duration, error) {
+		return 0, fmt.Errorf("clockify: invalid duration %q", s)
+	}
+
+	rest := s
+	negative := strings.HasPrefix(rest, "-")
+	if negative {
+		rest = rest[1:]
+	}
+	if !strings.HasPrefix(rest, "P") || rest == "P" {
+		return invalid()
+	}
+	rest = rest[1:]
+
+	var total int64
+	part, last := 0, -1
+	fraction := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if part == 1 || len(rest) == 1 {
+				return invalid()
+			}
+			part, last = 1, -1
+			rest = rest[1:]
+			continue
+		}
+
+		i := strings.IndexFunc(rest, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if i <= 0 || fraction {
+			return invalid()
+		}
+		fraction = strings.ContainsAny(rest[:i], ".,")
+
+		unit, ok := durationUnits[part][rest[i]]
+		if !ok {
+			return invalid()
+		}
+		position := strings.IndexByte(durationOrder[part], rest[i])
+		if position <= last {
+			return invalid()
+		}
+		last = position
+		value, ok := parseDurationValue(rest[:i], unit)
+		if !ok || value > math.MaxInt64-total {
+			return invalid()
+		}
+
+		total += value
+		rest = rest[i+1:]
+	}
+
+	if negative {
+		total = -total
+	}
+	return Duration(total), nil
+}
+
+// parseDurationValue returns a number of units given in decimal, with either
+// a point or a comma as the decimal separator, as a duration in nanoseconds.
+func parseDurationValue(s string, unit time.Duration) (int64, bool) {
+	whole, fraction := s, ""
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return 0, false
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || n > math.MaxInt64/int64(unit) {
+		return 0, false
+	}
+	value := n * int64(unit)
+
+	if fraction != "" {
+		f, err := strconv.ParseFloat("0."+fraction, 64)
+		if err != nil {
+			return 0, false
+		}
+		extra := int64(math.Round(f * float64(unit)))
+		if extra > math.MaxInt64-value {
+			return 0, false
+		}
+		value += extra
+	}
+
+	return value, true
+}
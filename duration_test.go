@@ -0,0 +1,70 @@
+package clockify
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"PT0S", 0},
+		{"PT1H30M15S", time.Hour + 30*time.Minute + 15*time.Second},
+		{"P1DT0.5S", 24*time.Hour + 500*time.Millisecond},
+		{"P1W2D", 9 * 24 * time.Hour},
+		{"PT1,5M", 90 * time.Second},
+		{"PT.5S", 500 * time.Millisecond},
+		{"PT1H1.5M", time.Hour + 90*time.Second},
+		{"-PT2H", -2 * time.Hour},
+	}
+	for _, test := range tests {
+		got, err := ParseDuration(test.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", test.in, err)
+			continue
+		}
+		if got.Duration() != test.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", test.in, got.Duration(), test.want)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, in := range []string{
+		"P", "PT", "1H", "PT1", "PTH", "P1H", "PT1D", "P1DT",
+		"PT1H1H", "PT1S1H", "PT1M1H", "P1D1W", "P1DT1HT1M",
+		"PT.S", "PT,M", "P.DT1H",
+		"PT1.5H30M", "PT0,5M1S", "P1.5DT1H",
+	} {
+		if d, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want an error", in, d.Duration())
+		}
+	}
+}
+
+func FuzzDuration(f *testing.F) {
+	for _, d := range []time.Duration{0, time.Nanosecond, 90 * time.Minute, -26*time.Hour - 500*time.Millisecond, math.MaxInt64} {
+		f.Add(int64(d))
+	}
+
+	f.Fuzz(func(t *testing.T, n int64) {
+		if n == math.MinInt64 {
+			// The magnitude of the smallest duration does not fit in a
+			// Duration, so it cannot be parsed back.
+			t.Skip()
+		}
+
+		d := Duration(n)
+		parsed, err := ParseDuration(d.String())
+		if err != nil {
+			t.Fatalf("ParseDuration(%q): %v", d.String(), err)
+		}
+		if parsed != d {
+			t.Fatalf("ParseDuration(%q) = %d, want %d", d.String(), parsed, d)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoRunningTimeEntry is returned when stopping the timer of a user who has
+// no running time entry.
+var ErrNoRunningTimeEntry = errors.New("clockify: no running time entry")
+
+// APIError is returned when the Clockify API answers a request with an error
+// status.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the HTTP status line of the response, e.g. "404 Not Found".
+	Status string
+	// Code is the Clockify error code from the response body, if any.
+	Code int
+	// Message is the Clockify error message from the response body, if any.
+	Message string
+	// Method and URL identify the request that failed.
+	Method string
+	URL    string
+	// Header holds the response headers.
+	Header http.Header
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("clockify: %s %s: %s: %s (code %d)", e.Method, e.URL, e.Status, e.Message, e.Code)
+	}
+	return fmt.Sprintf("clockify: %s %s: %s", e.Method, e.URL, e.Status)
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Header:     resp.Header,
+		Body:       body,
+	}
+
+	var payload struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.Message
+		apiErr.Code = payload.Code
+	}
+
+	return apiErr
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError with status 401.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with status 403.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is an APIError with status 429.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
@@ -1,5 +1,5 @@
 module github.com/kinoba/go-clockify
 
-go 1.14
+go 1.18
 
 require github.com/davecgh/go-spew v1.1.1
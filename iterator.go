@@ -0,0 +1,344 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// defaultPageSize is the page size Clockify uses when none is requested.
+const defaultPageSize = 50
+
+// ErrTooManyItems is returned by Collect when a list holds more items than
+// the caller allowed for.
+var ErrTooManyItems = errors.New("clockify: list holds more items than allowed")
+
+// pageFunc fetches a single page of a list, numbered from 1.
+type pageFunc func(ctx context.Context, page, pageSize int) ([]interface{}, error)
+
+// Iterator walks through the items of a paginated Clockify list, fetching
+// pages as it advances. The list methods return typed wrappers around it,
+// which are used like this:
+//
+//	iter := session.ListProjects(workspaceID)
+//	for iter.Next() {
+//		project := iter.Item()
+//		...
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	ctx      context.Context
+	fetch    pageFunc
+	pageSize int
+	prefetch int
+
+	page  int
+	items []interface{}
+	item  interface{}
+	done  bool
+	err   error
+}
+
+func newIterator(ctx context.Context, pageSize int, fetch pageFunc) *Iterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &Iterator{ctx: ctx, fetch: fetch, pageSize: pageSize}
+}
+
+// Prefetch makes the iterator fetch the given number of pages concurrently
+// whenever it runs out of items. This speeds up walking long lists at the
+// cost of a few requests past the end of the list. It must be called before
+// the first call to Next.
+func (iter *Iterator) Prefetch(pages int) {
+	iter.prefetch = pages
+}
+
+// Next advances the iterator to the next item, fetching more pages if needed.
+// It returns false once all items have been read or an error occurred.
+func (iter *Iterator) Next() bool {
+	for len(iter.items) == 0 {
+		if iter.done || iter.err != nil {
+			return false
+		}
+		iter.fetchPages()
+	}
+
+	iter.item, iter.items = iter.items[0], iter.items[1:]
+	return true
+}
+
+// Item returns the item the iterator is positioned at.
+func (iter *Iterator) Item() interface{} {
+	return iter.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iter *Iterator) Err() error {
+	return iter.err
+}
+
+// Collect reads all remaining items. If max is positive and the list holds
+// more than max items, the first max items are returned with ErrTooManyItems.
+func (iter *Iterator) Collect(max int) ([]interface{}, error) {
+	var items []interface{}
+	for iter.Next() {
+		if max > 0 && len(items) == max {
+			return items, ErrTooManyItems
+		}
+		items = append(items, iter.Item())
+	}
+	return items, iter.Err()
+}
+
+func (iter *Iterator) fetchPages() {
+	count := iter.prefetch
+	if count < 1 {
+		count = 1
+	}
+
+	type result struct {
+		items []interface{}
+		err   error
+	}
+	results := make([]result, count)
+
+	if count == 1 {
+		results[0].items, results[0].err = iter.fetch(iter.ctx, iter.page+1, iter.pageSize)
+	} else {
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i].items, results[i].err = iter.fetch(iter.ctx, iter.page+1+i, iter.pageSize)
+			}(i)
+		}
+		wg.Wait()
+	}
+	iter.page += count
+
+	for _, result := range results {
+		if result.err != nil {
+			iter.err = result.err
+			return
+		}
+		iter.items = append(iter.items, result.items...)
+		if len(result.items) < iter.pageSize {
+			iter.done = true
+			return
+		}
+	}
+}
+
+// listPages returns an iterator over a list endpoint taking the page and
+// page-size query parameters. decode turns the body of a page into items as
+// it is read.
+func (session *Session) listPages(ctx context.Context, path string, params url.Values, pageSize int, decode func(io.Reader) ([]interface{}, error)) *Iterator {
+	return newIterator(ctx, pageSize, func(ctx context.Context, page, pageSize int) ([]interface{}, error) {
+		pageParams := url.Values{}
+		for key, values := range params {
+			pageParams[key] = values
+		}
+		pageParams.Set("page", strconv.Itoa(page))
+		pageParams.Set("page-size", strconv.Itoa(pageSize))
+
+		body, err := session.stream(ctx, "GET", session.apiURL(), path, pageParams, nil, "", true)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		return decode(body)
+	})
+}
+
+// decodeItems decodes a JSON array into the slice pointed to by slicePtr and
+// returns its elements as items.
+func decodeItems(r io.Reader, slicePtr interface{}) ([]interface{}, error) {
+	if err := json.NewDecoder(r).Decode(slicePtr); err != nil {
+		return nil, err
+	}
+
+	slice := reflect.ValueOf(slicePtr).Elem()
+	items := make([]interface{}, slice.Len())
+	for i := range items {
+		items[i] = slice.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// TimeEntryIterator walks through a paginated list of time entries.
+type TimeEntryIterator struct {
+	*Iterator
+}
+
+// Item returns the time entry the iterator is positioned at.
+func (iter *TimeEntryIterator) Item() TimeEntry {
+	entry, _ := iter.Iterator.Item().(TimeEntry)
+	return entry
+}
+
+// Collect reads all remaining time entries, see Iterator.Collect.
+func (iter *TimeEntryIterator) Collect(max int) ([]TimeEntry, error) {
+	items, err := iter.Iterator.Collect(max)
+	entries := make([]TimeEntry, len(items))
+	for i, item := range items {
+		entries[i] = item.(TimeEntry)
+	}
+	return entries, err
+}
+
+// ProjectIterator walks through a paginated list of projects.
+type ProjectIterator struct {
+	*Iterator
+}
+
+// Item returns the project the iterator is positioned at.
+func (iter *ProjectIterator) Item() Project {
+	project, _ := iter.Iterator.Item().(Project)
+	return project
+}
+
+// Collect reads all remaining projects, see Iterator.Collect.
+func (iter *ProjectIterator) Collect(max int) ([]Project, error) {
+	items, err := iter.Iterator.Collect(max)
+	projects := make([]Project, len(items))
+	for i, item := range items {
+		projects[i] = item.(Project)
+	}
+	return projects, err
+}
+
+// ClientIterator walks through a paginated list of clients.
+type ClientIterator struct {
+	*Iterator
+}
+
+// Item returns the client the iterator is positioned at.
+func (iter *ClientIterator) Item() Client {
+	client, _ := iter.Iterator.Item().(Client)
+	return client
+}
+
+// Collect reads all remaining clients, see Iterator.Collect.
+func (iter *ClientIterator) Collect(max int) ([]Client, error) {
+	items, err := iter.Iterator.Collect(max)
+	clients := make([]Client, len(items))
+	for i, item := range items {
+		clients[i] = item.(Client)
+	}
+	return clients, err
+}
+
+// TagIterator walks through a paginated list of tags.
+type TagIterator struct {
+	*Iterator
+}
+
+// Item returns the tag the iterator is positioned at.
+func (iter *TagIterator) Item() Tag {
+	tag, _ := iter.Iterator.Item().(Tag)
+	return tag
+}
+
+// Collect reads all remaining tags, see Iterator.Collect.
+func (iter *TagIterator) Collect(max int) ([]Tag, error) {
+	items, err := iter.Iterator.Collect(max)
+	tags := make([]Tag, len(items))
+	for i, item := range items {
+		tags[i] = item.(Tag)
+	}
+	return tags, err
+}
+
+// TaskIterator walks through a paginated list of tasks.
+type TaskIterator struct {
+	*Iterator
+}
+
+// Item returns the task the iterator is positioned at.
+func (iter *TaskIterator) Item() Task {
+	task, _ := iter.Iterator.Item().(Task)
+	return task
+}
+
+// Collect reads all remaining tasks, see Iterator.Collect.
+func (iter *TaskIterator) Collect(max int) ([]Task, error) {
+	items, err := iter.Iterator.Collect(max)
+	tasks := make([]Task, len(items))
+	for i, item := range items {
+		tasks[i] = item.(Task)
+	}
+	return tasks, err
+}
+
+// UserIterator walks through a paginated list of users.
+type UserIterator struct {
+	*Iterator
+}
+
+// Item returns the user the iterator is positioned at.
+func (iter *UserIterator) Item() User {
+	user, _ := iter.Iterator.Item().(User)
+	return user
+}
+
+// Collect reads all remaining users, see Iterator.Collect.
+func (iter *UserIterator) Collect(max int) ([]User, error) {
+	items, err := iter.Iterator.Collect(max)
+	users := make([]User, len(items))
+	for i, item := range items {
+		users[i] = item.(User)
+	}
+	return users, err
+}
+
+// UserGroupIterator walks through a paginated list of user groups.
+type UserGroupIterator struct {
+	*Iterator
+}
+
+// Item returns the user group the iterator is positioned at.
+func (iter *UserGroupIterator) Item() UserGroup {
+	group, _ := iter.Iterator.Item().(UserGroup)
+	return group
+}
+
+// Collect reads all remaining user groups, see Iterator.Collect.
+func (iter *UserGroupIterator) Collect(max int) ([]UserGroup, error) {
+	items, err := iter.Iterator.Collect(max)
+	groups := make([]UserGroup, len(items))
+	for i, item := range items {
+		groups[i] = item.(UserGroup)
+	}
+	return groups, err
+}
+
+// DetailedTimeEntryIterator walks through the entries of a detailed report.
+type DetailedTimeEntryIterator struct {
+	*Iterator
+}
+
+// Item returns the entry the iterator is positioned at.
+func (iter *DetailedTimeEntryIterator) Item() DetailedTimeEntry {
+	entry, _ := iter.Iterator.Item().(DetailedTimeEntry)
+	return entry
+}
+
+// Collect reads all remaining entries, see Iterator.Collect.
+func (iter *DetailedTimeEntryIterator) Collect(max int) ([]DetailedTimeEntry, error) {
+	items, err := iter.Iterator.Collect(max)
+	entries := make([]DetailedTimeEntry, len(items))
+	for i, item := range items {
+		entries[i] = item.(DetailedTimeEntry)
+	}
+	return entries, err
+}
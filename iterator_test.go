@@ -0,0 +1,162 @@
+package clockify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tagServer serves a list of total tags, answering requests for failPage
+// with a server error. It records the pages requested.
+type tagServer struct {
+	*httptest.Server
+	total    int
+	failPage int
+
+	mu    sync.Mutex
+	pages []int
+}
+
+func newTagServer(t *testing.T, total, failPage int) *tagServer {
+	server := &tagServer{total: total, failPage: failPage}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page-size"))
+
+		server.mu.Lock()
+		server.pages = append(server.pages, page)
+		server.mu.Unlock()
+
+		if page == server.failPage {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var tags []string
+		for i := (page - 1) * pageSize; i < page*pageSize && i < server.total; i++ {
+			tags = append(tags, fmt.Sprintf(`{"id":"tag%d"}`, i))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(tags, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *tagServer) list(pageSize int) *TagIterator {
+	session := NewClient("token", WithBaseURL(server.URL))
+	return &TagIterator{session.listPages(context.Background(), "/tags", nil, pageSize, decodeTags)}
+}
+
+func (server *tagServer) requested() []int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	pages := append([]int(nil), server.pages...)
+	sort.Ints(pages)
+	return pages
+}
+
+func checkTags(t *testing.T, tags []Tag, count int) {
+	t.Helper()
+	if len(tags) != count {
+		t.Fatalf("got %d tags, want %d", len(tags), count)
+	}
+	for i, tag := range tags {
+		if want := fmt.Sprintf("tag%d", i); tag.ID != want {
+			t.Fatalf("got tag %s at %d, want %s", tag.ID, i, want)
+		}
+	}
+}
+
+func TestIteratorStopsOnShortPage(t *testing.T) {
+	server := newTagServer(t, 25, 0)
+
+	tags, err := server.list(10).Collect(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTags(t, tags, 25)
+	if pages := server.requested(); !reflect.DeepEqual(pages, []int{1, 2, 3}) {
+		t.Errorf("got pages %v, want [1 2 3]", pages)
+	}
+}
+
+func TestIteratorStopsOnEmptyPage(t *testing.T) {
+	server := newTagServer(t, 20, 0)
+
+	tags, err := server.list(10).Collect(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTags(t, tags, 20)
+	if pages := server.requested(); !reflect.DeepEqual(pages, []int{1, 2, 3}) {
+		t.Errorf("got pages %v, want [1 2 3]", pages)
+	}
+}
+
+func TestIteratorCollectMax(t *testing.T) {
+	server := newTagServer(t, 25, 0)
+
+	tags, err := server.list(10).Collect(12)
+	if err != ErrTooManyItems {
+		t.Errorf("got error %v, want ErrTooManyItems", err)
+	}
+	checkTags(t, tags, 12)
+
+	tags, err = server.list(10).Collect(25)
+	if err != nil {
+		t.Errorf("got error %v for a list of exactly max items", err)
+	}
+	checkTags(t, tags, 25)
+}
+
+func TestIteratorPrefetch(t *testing.T) {
+	server := newTagServer(t, 35, 0)
+
+	iter := server.list(10)
+	iter.Prefetch(3)
+	tags, err := iter.Collect(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTags(t, tags, 35)
+
+	// Pages 1 to 3 are fetched together, then 4 to 6, of which page 4 is
+	// short and ends the list.
+	if pages := server.requested(); !reflect.DeepEqual(pages, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("got pages %v, want [1 2 3 4 5 6]", pages)
+	}
+}
+
+func TestIteratorPrefetchError(t *testing.T) {
+	server := newTagServer(t, 100, 2)
+
+	iter := server.list(10)
+	iter.Prefetch(3)
+	tags, err := iter.Collect(0)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("got error %v, want a 400 APIError", err)
+	}
+	// The items of the pages before the failed one are still returned.
+	checkTags(t, tags, 10)
+}
+
+func TestIteratorPrefetchIgnoresErrorsPastTheEnd(t *testing.T) {
+	server := newTagServer(t, 5, 3)
+
+	iter := server.list(10)
+	iter.Prefetch(3)
+	tags, err := iter.Collect(0)
+	if err != nil {
+		t.Errorf("got error %v from a page past the end of the list", err)
+	}
+	checkTags(t, tags, 5)
+}
@@ -9,6 +9,7 @@ package clockify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,18 +18,27 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Clockify service constants
 const (
-	ClockifyAPI       = "https://api.clockify.me/api/v1"
-	DefaultAppName = "go-clockify"
+	ClockifyAPI        = "https://api.clockify.me/api/v1"
+	ClockifyReportsAPI = "https://reports.api.clockify.me/v1"
+	DefaultAppName     = "go-clockify"
+)
+
+// Sort orders accepted by list filters.
+const (
+	SortAscending  = "ASCENDING"
+	SortDescending = "DESCENDING"
 )
 
 var (
-	dlog   = log.New(os.Stderr, "[clockify] ", log.LstdFlags)
-	client = &http.Client{}
+	dlog = log.New(os.Stderr, "[clockify] ", log.LstdFlags)
 
 	// AppName is the application name used when creating timers.
 	AppName = DefaultAppName
@@ -39,6 +49,14 @@ var (
 // Session represents an active connection to the Clockify REST API.
 type Session struct {
 	APIToken string
+
+	httpClient *http.Client
+	baseURL    string
+	reportsURL string
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    *RateLimiter
 }
 
 // AccountSettings represents a user account settings.
@@ -64,55 +82,9 @@ type Account struct {
 	Settings     	AccountSettings `json:"settings"`
 }
 
-// Workspace represents a user workspace.
-type Workspace struct {
-	ID              string    `json:"id"`
-	RoundingMinutes int    `json:"rounding_minutes"`
-	// Rounding        int    `json:"rounding"`
-	Name            string `json:"name"`
-	// Premium         bool   `json:"premium"`
-}
-
-// Client represents a client.
-type Client struct {
-	Wid   string    `json:"workspaceId"`
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	// Notes string `json:"notes"`
-}
-
-// Project represents a project.
-type Project struct {
-	Wid             string     `json:"workspaceId"`
-	ID              string     `json:"id"`
-	// Cid             int        `json:"cid"`
-	Name            string     `json:"name"`
-	Active          bool       `json:"archived"`
-	Billable        bool       `json:"billable"`
-}
-
-// IsActive indicates whether a project exists and is active
-func (p *Project) IsActive() bool {
-	return p.Active
-}
-
-// Task represents a task.
-type Task struct {
-	Pid  string `json:"projectId"`
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
-// Tag represents a tag.
-type Tag struct {
-	Wid  string `json:"workspaceId"`
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
 // TimeInterval represents a time interval.
 type TimeInterval struct {
-	Duration  string `json:"duration"`
+	Duration  Duration   `json:"duration"`
 	Stop      *time.Time `json:"end,omitempty"`
 	Start     *time.Time `json:"start,omitempty"`
 }
@@ -121,12 +93,38 @@ type TimeInterval struct {
 type TimeEntry struct {
 	Wid          string       `json:"workspaceId,omitempty"`
 	ID           string       `json:"id,omitempty"`
-	Pid          string       `json:"pid"`
+	UserID       string       `json:"userId,omitempty"`
+	Pid          string       `json:"projectId"`
 	Tid          string       `json:"taskId"`
 	Description  string       `json:"description,omitempty"`
 	TimeInterval TimeInterval `json:"timeInterval"`
 	Tags         []string     `json:"tagIds"`
 	Billable     bool         `json:"billable"`
+
+	// Project, Task and TagDetails are only filled in for entries listed
+	// with TimeEntryFilter.Hydrated set.
+	Project    *Project `json:"project,omitempty"`
+	Task       *Task    `json:"task,omitempty"`
+	TagDetails []Tag    `json:"tags,omitempty"`
+}
+
+// TimeEntryFilter narrows down the time entries returned by ListTimeEntries.
+// Zero fields are not used for filtering.
+type TimeEntryFilter struct {
+	Start       time.Time
+	End         time.Time
+	Project     string
+	Task        string
+	Tags        []string
+	Description string
+	// InProgress restricts the results to the running time entry.
+	InProgress bool
+	// Hydrated makes Clockify return the project, task and tags of each
+	// entry instead of just their IDs.
+	Hydrated bool
+	// PageSize is the number of entries fetched per request. Clockify
+	// defaults to 50.
+	PageSize int
 }
 
 // TimeEntryRequest represents a single time entry request.
@@ -140,24 +138,43 @@ type TimeEntryRequest struct {
 	Billable     bool         `json:"billable,omitempty"`
 }
 
-// type DetailedTimeEntry struct {
-// 	ID              int        `json:"id"`
-// 	Pid             int        `json:"pid"`
-// 	Tid             int        `json:"tid"`
-// 	Uid             int        `json:"uid"`
-// 	User            string     `json:"user,omitempty"`
-// 	Description     string     `json:"description"`
-// 	Project         string     `json:"project"`
-// 	ProjectColor    string     `json:"project_color"`
-// 	ProjectHexColor string     `json:"project_hex_color"`
-// 	Client          string     `json:"client"`
-// 	Start           *time.Time `json:"start"`
-// 	End             *time.Time `json:"end"`
-// 	Updated         *time.Time `json:"updated"`
-// 	Duration        int64      `json:"dur"`
-// 	Billable        bool       `json:"billable"`
-// 	Tags            []string   `json:"tags"`
-// }
+// Rate represents an hourly or cost rate. The amount is expressed in cents
+// of the currency.
+type Rate struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// Estimate represents a time estimate. Type is either "AUTO", in which case
+// the estimate is computed from the tasks of the project, or "MANUAL".
+type Estimate struct {
+	Estimate Duration `json:"estimate"`
+	Type     string   `json:"type,omitempty"`
+}
+
+// Membership represents the membership of a user in a workspace, project or
+// user group, with the rates that apply to it.
+type Membership struct {
+	UserID           string `json:"userId"`
+	TargetID         string `json:"targetId"`
+	MembershipType   string `json:"membershipType"`
+	MembershipStatus string `json:"membershipStatus"`
+	HourlyRate       *Rate  `json:"hourlyRate"`
+	CostRate         *Rate  `json:"costRate"`
+}
+
+// UpdateTimeEntryRequest represents a request replacing the contents of an
+// existing time entry. Build one from a fetched entry with NewTimeEntryUpdate
+// so that unchanged fields keep their current values.
+type UpdateTimeEntryRequest struct {
+	Start       string   `json:"start"`
+	End         string   `json:"end,omitempty"`
+	Billable    bool     `json:"billable"`
+	Description string   `json:"description"`
+	Pid         string   `json:"projectId,omitempty"`
+	Tid         string   `json:"taskId,omitempty"`
+	Tags        []string `json:"tagIds"`
+}
 
 // functions ////////////////////////////
 
@@ -166,10 +183,41 @@ func OpenSession(apiToken string) Session {
 	return Session{APIToken: apiToken}
 }
 
+// NewClient returns a session using an existing API token, configured with
+// the given options. Sessions created this way are independent of each other
+// and may be pointed at different endpoints.
+func NewClient(apiToken string, options ...Option) *Session {
+	session := &Session{
+		APIToken:   apiToken,
+		httpClient: &http.Client{},
+		baseURL:    ClockifyAPI,
+		reportsURL: ClockifyReportsAPI,
+		userAgent:  AppName,
+	}
+
+	for _, option := range options {
+		option(session)
+	}
+
+	if session.timeout > 0 {
+		httpClient := *session.httpClient
+		httpClient.Timeout = session.timeout
+		session.httpClient = &httpClient
+	}
+
+	return session
+}
+
 // GetAccount returns a user's account information, including a list of active
 // projects and timers.
 func (session *Session) GetAccount() (Account, error) {
-	data, err := session.get(ClockifyAPI, "/user", nil)
+	return session.GetAccountContext(context.Background())
+}
+
+// GetAccountContext is like GetAccount but uses the given context for the
+// request.
+func (session *Session) GetAccountContext(ctx context.Context) (Account, error) {
+	data, err := session.get(ctx, session.apiURL(), "/user", nil)
 	if err != nil {
 		return Account{}, err
 	}
@@ -181,15 +229,27 @@ func (session *Session) GetAccount() (Account, error) {
 
 // StartTimeEntry creates a new time entry.
 func (session *Session) StartTimeEntry(workspaceID string, timeEntryRequest TimeEntryRequest) (TimeEntry, error) {
+	return session.StartTimeEntryContext(context.Background(), workspaceID, timeEntryRequest)
+}
+
+// StartTimeEntryContext is like StartTimeEntry but uses the given context for
+// the request.
+func (session *Session) StartTimeEntryContext(ctx context.Context, workspaceID string, timeEntryRequest TimeEntryRequest) (TimeEntry, error) {
 	path := fmt.Sprintf("/workspaces/%s/time-entries", workspaceID)
-	respData, err := session.post(ClockifyAPI, path, timeEntryRequest)
+	respData, err := session.post(ctx, session.apiURL(), path, timeEntryRequest)
 	return requestTimeEntry(respData, err)
 }
 
 // GetTimeEntry returns the time entry
 func (session *Session) GetTimeEntry(workspaceID, timeEntryID string) (TimeEntry, error) {
+	return session.GetTimeEntryContext(context.Background(), workspaceID, timeEntryID)
+}
+
+// GetTimeEntryContext is like GetTimeEntry but uses the given context for the
+// request.
+func (session *Session) GetTimeEntryContext(ctx context.Context, workspaceID, timeEntryID string) (TimeEntry, error) {
 	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, timeEntryID)
-	data, err := session.get(ClockifyAPI, path, nil)
+	data, err := session.get(ctx, session.apiURL(), path, nil)
 	if err != nil {
 		return TimeEntry{}, err
 	}
@@ -199,32 +259,273 @@ func (session *Session) GetTimeEntry(workspaceID, timeEntryID string) (TimeEntry
 
 // DeleteTimeEntry deletes a time entry.
 func (session *Session) DeleteTimeEntry(workspaceID, timeEntryID string) ([]byte, error) {
+	return session.DeleteTimeEntryContext(context.Background(), workspaceID, timeEntryID)
+}
+
+// DeleteTimeEntryContext is like DeleteTimeEntry but uses the given context
+// for the request.
+func (session *Session) DeleteTimeEntryContext(ctx context.Context, workspaceID, timeEntryID string) ([]byte, error) {
 	dlog.Printf("Deleting time entry %v", timeEntryID)
 	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, timeEntryID)
-	return session.delete(ClockifyAPI, path)
+	return session.delete(ctx, session.apiURL(), path)
 }
 
-// GetTimeEntries returns a list of time entries
-// func (session *Session) GetTimeEntries(startDate, endDate time.Time) ([]TimeEntry, error) {
-// 	params := make(map[string]string)
-// 	params["start_date"] = startDate.Format(time.RFC3339)
-// 	params["end_date"] = endDate.Format(time.RFC3339)
-// 	data, err := session.get(ClockifyAPI, "/time-entries", params)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	results := make([]TimeEntry, 0)
-// 	err = json.Unmarshal(data, &results)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return results, nil
-// }
+// GetTimeEntries returns all time entries of a user matching the filter,
+// fetching as many pages as needed.
+func (session *Session) GetTimeEntries(workspaceID, userID string, filter TimeEntryFilter) ([]TimeEntry, error) {
+	return session.GetTimeEntriesContext(context.Background(), workspaceID, userID, filter)
+}
+
+// GetTimeEntriesContext is like GetTimeEntries but uses the given context for
+// the requests.
+func (session *Session) GetTimeEntriesContext(ctx context.Context, workspaceID, userID string, filter TimeEntryFilter) ([]TimeEntry, error) {
+	return session.ListTimeEntriesContext(ctx, workspaceID, userID, filter).Collect(0)
+}
+
+// ListTimeEntries returns an iterator over the time entries of a user matching
+// the filter. Pages are fetched as the iterator advances.
+func (session *Session) ListTimeEntries(workspaceID, userID string, filter TimeEntryFilter) *TimeEntryIterator {
+	return session.ListTimeEntriesContext(context.Background(), workspaceID, userID, filter)
+}
+
+// ListTimeEntriesContext is like ListTimeEntries but uses the given context
+// for the requests.
+func (session *Session) ListTimeEntriesContext(ctx context.Context, workspaceID, userID string, filter TimeEntryFilter) *TimeEntryIterator {
+	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID)
+	return &TimeEntryIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeTimeEntries)}
+}
+
+func (filter TimeEntryFilter) params() url.Values {
+	params := url.Values{}
+	if !filter.Start.IsZero() {
+		params.Set("start", filter.Start.UTC().Format(time.RFC3339))
+	}
+	if !filter.End.IsZero() {
+		params.Set("end", filter.End.UTC().Format(time.RFC3339))
+	}
+	if filter.Project != "" {
+		params.Set("project", filter.Project)
+	}
+	if filter.Task != "" {
+		params.Set("task", filter.Task)
+	}
+	for _, tag := range filter.Tags {
+		params.Add("tags", tag)
+	}
+	if filter.Description != "" {
+		params.Set("description", filter.Description)
+	}
+	if filter.InProgress {
+		params.Set("in-progress", "true")
+	}
+	if filter.Hydrated {
+		params.Set("hydrated", "true")
+	}
+	return params
+}
+
+// isBelow reports whether requestURL has the scheme and host of baseURL and a
+// path at or below the path of baseURL.
+func isBelow(requestURL *url.URL, baseURL string) bool {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	if requestURL.Scheme != base.Scheme || requestURL.Host != base.Host || requestURL.User != nil {
+		return false
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	requestPath := path.Clean("/" + requestURL.Path)
+	return requestPath == basePath || strings.HasPrefix(requestPath, basePath+"/")
+}
+
+// Raw sends a request to the Clockify API and returns the response body
+// without decoding it, for endpoints this package does not cover or for
+// callers that want to decode responses themselves. path starts with a slash
+// and is relative to the API base URL, unless it is an absolute URL, which
+// allows requests to the reports API. The resulting URL must point below the
+// API or reports API base URL of the session, so that the API token is not
+// sent to other hosts. data, if not nil, is sent as JSON. The caller must
+// close the returned body.
+func (session *Session) Raw(method, path string, data interface{}) (io.ReadCloser, error) {
+	return session.RawContext(context.Background(), method, path, data)
+}
+
+// RawContext is like Raw but uses the given context for the request. The
+// context also covers reading the body.
+func (session *Session) RawContext(ctx context.Context, method, path string, data interface{}) (io.ReadCloser, error) {
+	requestURL := session.apiURL()
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		requestURL = ""
+	} else if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("clockify: path %q does not start with a slash", path)
+	}
+
+	parsed, err := url.Parse(requestURL + path)
+	if err != nil {
+		return nil, err
+	}
+	if !isBelow(parsed, session.apiURL()) && !isBelow(parsed, session.reportsAPIURL()) {
+		return nil, fmt.Errorf("clockify: %s is not a Clockify API URL", requestURL+path)
+	}
+	return session.stream(ctx, method, requestURL, path, nil, data, "", isIdempotent(method))
+}
+
+// GetRunningTimeEntry returns the time entry a user is currently tracking.
+// The boolean result is false if the user has no running time entry.
+func (session *Session) GetRunningTimeEntry(workspaceID, userID string) (TimeEntry, bool, error) {
+	return session.GetRunningTimeEntryContext(context.Background(), workspaceID, userID)
+}
+
+// GetRunningTimeEntryContext is like GetRunningTimeEntry but uses the given
+// context for the request.
+func (session *Session) GetRunningTimeEntryContext(ctx context.Context, workspaceID, userID string) (TimeEntry, bool, error) {
+	iter := session.ListTimeEntriesContext(ctx, workspaceID, userID, TimeEntryFilter{InProgress: true, PageSize: 1})
+	if !iter.Next() {
+		return TimeEntry{}, false, iter.Err()
+	}
+	return iter.Item(), true, nil
+}
+
+// UpdateTimeEntry replaces the contents of a time entry and returns the
+// updated entry.
+func (session *Session) UpdateTimeEntry(workspaceID, timeEntryID string, req UpdateTimeEntryRequest) (TimeEntry, error) {
+	return session.UpdateTimeEntryContext(context.Background(), workspaceID, timeEntryID, req)
+}
+
+// UpdateTimeEntryContext is like UpdateTimeEntry but uses the given context
+// for the request.
+func (session *Session) UpdateTimeEntryContext(ctx context.Context, workspaceID, timeEntryID string, req UpdateTimeEntryRequest) (TimeEntry, error) {
+	dlog.Printf("Updating time entry %v", timeEntryID)
+	path := fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, timeEntryID)
+	respData, err := session.put(ctx, session.apiURL(), path, req)
+	return requestTimeEntry(respData, err)
+}
+
+// TimeEntryUpdate builds an UpdateTimeEntryRequest starting from the current
+// state of a time entry.
+//
+//	entry, err := session.GetTimeEntry(workspaceID, timeEntryID)
+//	...
+//	req := clockify.NewTimeEntryUpdate(entry).
+//		SetDescription("Code review").
+//		SetDuration(45 * time.Minute).
+//		Request()
+//	entry, err = session.UpdateTimeEntry(workspaceID, timeEntryID, req)
+type TimeEntryUpdate struct {
+	req UpdateTimeEntryRequest
+	// start is nil until the entry has a start time. duration is set while
+	// a duration waits for a start time to be measured from.
+	start    *time.Time
+	duration *time.Duration
+}
+
+// NewTimeEntryUpdate returns a builder holding the current values of entry.
+func NewTimeEntryUpdate(entry TimeEntry) *TimeEntryUpdate {
+	update := &TimeEntryUpdate{
+		req: UpdateTimeEntryRequest{
+			Billable:    entry.Billable,
+			Description: entry.Description,
+			Pid:         entry.Pid,
+			Tid:         entry.Tid,
+			Tags:        append([]string{}, entry.Tags...),
+		},
+	}
+	if entry.TimeInterval.Start != nil {
+		update.SetStart(*entry.TimeInterval.Start)
+	}
+	if entry.TimeInterval.Stop != nil {
+		update.SetEnd(*entry.TimeInterval.Stop)
+	}
+	return update
+}
+
+// SetDescription changes the description of the entry.
+func (update *TimeEntryUpdate) SetDescription(description string) *TimeEntryUpdate {
+	update.req.Description = description
+	return update
+}
+
+// SetProject moves the entry to another project. Since tasks belong to a
+// project, the task is cleared when the project changes.
+func (update *TimeEntryUpdate) SetProject(projectID string) *TimeEntryUpdate {
+	if projectID != update.req.Pid {
+		update.req.Tid = ""
+	}
+	update.req.Pid = projectID
+	return update
+}
+
+// SetTask changes the task of the entry.
+func (update *TimeEntryUpdate) SetTask(taskID string) *TimeEntryUpdate {
+	update.req.Tid = taskID
+	return update
+}
+
+// SetTags replaces the tags of the entry.
+func (update *TimeEntryUpdate) SetTags(tagIDs []string) *TimeEntryUpdate {
+	update.req.Tags = append([]string{}, tagIDs...)
+	return update
+}
+
+// SetBillable changes whether the entry is billable.
+func (update *TimeEntryUpdate) SetBillable(billable bool) *TimeEntryUpdate {
+	update.req.Billable = billable
+	return update
+}
+
+// SetStart changes the start time of the entry, leaving the end time as is,
+// unless SetDuration was called before the entry had a start time.
+func (update *TimeEntryUpdate) SetStart(start time.Time) *TimeEntryUpdate {
+	update.start = &start
+	update.req.Start = start.UTC().Format(time.RFC3339)
+	if update.duration != nil {
+		update.SetDuration(*update.duration)
+	}
+	return update
+}
+
+// SetEnd changes the end time of the entry, leaving the start time as is.
+func (update *TimeEntryUpdate) SetEnd(end time.Time) *TimeEntryUpdate {
+	update.duration = nil
+	update.req.End = end.UTC().Format(time.RFC3339)
+	return update
+}
+
+// SetInterval changes both the start and the end time of the entry.
+func (update *TimeEntryUpdate) SetInterval(start, end time.Time) *TimeEntryUpdate {
+	return update.SetStart(start).SetEnd(end)
+}
+
+// SetDuration changes the end time of the entry so that it lasts for the
+// given duration from its start time. If the entry has no start time yet, the
+// end time is set once one is given with SetStart.
+func (update *TimeEntryUpdate) SetDuration(duration time.Duration) *TimeEntryUpdate {
+	if update.start == nil {
+		update.duration = &duration
+		return update
+	}
+	return update.SetEnd(update.start.Add(duration))
+}
+
+// Request returns the request to pass to UpdateTimeEntry.
+func (update *TimeEntryUpdate) Request() UpdateTimeEntryRequest {
+	req := update.req
+	req.Tags = append([]string{}, update.req.Tags...)
+	return req
+}
 
 // ContinueTimeEntry continues a time entry by creating a new entry
 // with the same description. The new entry will have the same description and project ID as
 // the existing one.
 func (session *Session) ContinueTimeEntry(timer TimeEntry, duronly bool) (TimeEntry, error) {
+	return session.ContinueTimeEntryContext(context.Background(), timer, duronly)
+}
+
+// ContinueTimeEntryContext is like ContinueTimeEntry but uses the given
+// context for the request.
+func (session *Session) ContinueTimeEntryContext(ctx context.Context, timer TimeEntry, duronly bool) (TimeEntry, error) {
 	dlog.Printf("Continuing timer %v", timer)
 	var respData []byte
 	var err error
@@ -239,17 +540,39 @@ func (session *Session) ContinueTimeEntry(timer TimeEntry, duronly bool) (TimeEn
 	
 	path := fmt.Sprintf("/workspaces/%s/time-entries", timer.Wid)
 	
-	respData, err = session.post(ClockifyAPI, path, timeEntryRequest)
+	respData, err = session.post(ctx, session.apiURL(), path, timeEntryRequest)
 	
 	return requestTimeEntry(respData, err)
 }
 
 
-// StopTimeEntry stops a running time entry.
+// StopTimeEntry stops the running time entry of a user and returns it. If the
+// user has no running time entry, ErrNoRunningTimeEntry is returned.
 func (session *Session) StopTimeEntry(workspaceID, userID string) (TimeEntry, error) {
+	return session.StopTimeEntryContext(context.Background(), workspaceID, userID)
+}
+
+// StopTimeEntryContext is like StopTimeEntry but uses the given context for
+// the request.
+func (session *Session) StopTimeEntryContext(ctx context.Context, workspaceID, userID string) (TimeEntry, error) {
+	return session.StopTimeEntryAtContext(ctx, workspaceID, userID, time.Now())
+}
+
+// StopTimeEntryAt is like StopTimeEntry but stops the time entry at the given
+// end time instead of now.
+func (session *Session) StopTimeEntryAt(workspaceID, userID string, end time.Time) (TimeEntry, error) {
+	return session.StopTimeEntryAtContext(context.Background(), workspaceID, userID, end)
+}
+
+// StopTimeEntryAtContext is like StopTimeEntryAt but uses the given context
+// for the request.
+func (session *Session) StopTimeEntryAtContext(ctx context.Context, workspaceID, userID string, end time.Time) (TimeEntry, error) {
 	dlog.Printf("Stopping timer to user %s", userID)
-	path := fmt.Sprintf("/workspaces/{workspaceId}/user/{userId}/time-entries", workspaceID, userID)
-	respData, err := session.patch(ClockifyAPI, path, TimeEntryRequest{End: time.Now().UTC().Format(time.RFC3339)})
+	path := fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID)
+	respData, err := session.patch(ctx, session.apiURL(), path, TimeEntryRequest{End: end.UTC().Format(time.RFC3339)})
+	if IsNotFound(err) {
+		return TimeEntry{}, ErrNoRunningTimeEntry
+	}
 	return requestTimeEntry(respData, err)
 }
 
@@ -270,187 +593,17 @@ func (session *Session) StopTimeEntry(workspaceID, userID string) (TimeEntry, er
 // 		},
 // 	}
 // 	path := fmt.Sprintf("/time_entries/%v", entryID)
-// 	respData, err := session.post(ClockifyAPI, path, data)
+// 	respData, err := session.post(session.apiURL(), path, data)
 // 
 // 	return requestTimeEntry(respData, err)
 // }
 
 
-// // IsRunning returns true if the receiver is currently running.
-// func (e *TimeEntry) IsRunning() bool {
-// 	return e.Duration < 0
-// }
-
-// GetProjects allows to query for all projects in a workspace
-func (session *Session) GetProjects(workspaceID string) (projects []Project, err error) {
-	dlog.Printf("Getting projects for workspace %s", workspaceID)
-	path := fmt.Sprintf("/workspaces/%s/projects", workspaceID)
-	data,err := session.get(ClockifyAPI, path, nil)
-	if err != nil {
-		return
-	}
-
-	err = json.Unmarshal(data, &projects)
-	dlog.Printf("Unmarshaled '%s' into %#v\n", data, projects)
-	return
+// IsRunning returns true if the receiver has started and not yet stopped.
+func (e *TimeEntry) IsRunning() bool {
+	return e.TimeInterval.Start != nil && e.TimeInterval.Stop == nil
 }
 
-// // CreateProject creates a new project.
-// func (session *Session) CreateProject(name string, wid int) (proj Project, err error) {
-// 	dlog.Printf("Creating project %s", name)
-// 	data := map[string]interface{}{
-// 		"project": map[string]interface{}{
-// 			"name": name,
-// 			"wid":  wid,
-// 		},
-// 	}
-// 
-// 	respData, err := session.post(ClockifyAPI, "/projects", data)
-// 	if err != nil {
-// 		return proj, err
-// 	}
-// 
-// 	var entry struct {
-// 		Data Project `json:"data"`
-// 	}
-// 	err = json.Unmarshal(respData, &entry)
-// 	dlog.Printf("Unmarshaled '%s' into %#v\n", respData, entry)
-// 	if err != nil {
-// 		return proj, err
-// 	}
-// 
-// 	return entry.Data, nil
-// }
-// 
-// // UpdateProject changes information about an existing project.
-// func (session *Session) UpdateProject(project Project) (Project, error) {
-// 	dlog.Printf("Updating project %v", project)
-// 	data := map[string]interface{}{
-// 		"project": project,
-// 	}
-// 	path := fmt.Sprintf("/projects/%v", project.ID)
-// 	respData, err := session.put(ClockifyAPI, path, data)
-// 
-// 	if err != nil {
-// 		return Project{}, err
-// 	}
-// 
-// 	var entry struct {
-// 		Data Project `json:"data"`
-// 	}
-// 	err = json.Unmarshal(respData, &entry)
-// 	dlog.Printf("Unmarshaled '%s' into %#v\n", data, entry)
-// 	if err != nil {
-// 		return Project{}, err
-// 	}
-// 
-// 	return entry.Data, nil
-// }
-// 
-// // DeleteProject deletes a project.
-// func (session *Session) DeleteProject(project Project) ([]byte, error) {
-// 	dlog.Printf("Deleting project %v", project)
-// 	path := fmt.Sprintf("/projects/%v", project.ID)
-// 	return session.delete(ClockifyAPI, path)
-// }
-// 
-// // CreateTag creates a new tag.
-// func (session *Session) CreateTag(name string, wid int) (proj Tag, err error) {
-// 	dlog.Printf("Creating tag %s", name)
-// 	data := map[string]interface{}{
-// 		"tag": map[string]interface{}{
-// 			"name": name,
-// 			"wid":  wid,
-// 		},
-// 	}
-// 
-// 	respData, err := session.post(ClockifyAPI, "/tags", data)
-// 	if err != nil {
-// 		return proj, err
-// 	}
-// 
-// 	var entry struct {
-// 		Data Tag `json:"data"`
-// 	}
-// 	err = json.Unmarshal(respData, &entry)
-// 	dlog.Printf("Unmarshaled '%s' into %#v\n", respData, entry)
-// 	if err != nil {
-// 		return proj, err
-// 	}
-// 
-// 	return entry.Data, nil
-// }
-// 
-// // UpdateTag changes information about an existing tag.
-// func (session *Session) UpdateTag(tag Tag) (Tag, error) {
-// 	dlog.Printf("Updating tag %v", tag)
-// 	data := map[string]interface{}{
-// 		"tag": tag,
-// 	}
-// 	path := fmt.Sprintf("/tags/%v", tag.ID)
-// 	respData, err := session.put(ClockifyAPI, path, data)
-// 
-// 	if err != nil {
-// 		return Tag{}, err
-// 	}
-// 
-// 	var entry struct {
-// 		Data Tag `json:"data"`
-// 	}
-// 	err = json.Unmarshal(respData, &entry)
-// 	dlog.Printf("Unmarshaled '%s' into %#v\n", data, entry)
-// 	if err != nil {
-// 		return Tag{}, err
-// 	}
-// 
-// 	return entry.Data, nil
-// }
-// 
-// // DeleteTag deletes a tag.
-// func (session *Session) DeleteTag(tag Tag) ([]byte, error) {
-// 	dlog.Printf("Deleting tag %v", tag)
-// 	path := fmt.Sprintf("/tags/%v", tag.ID)
-// 	return session.delete(ClockifyAPI, path)
-// }
-// 
-// // GetClients returns a list of clients for the current account
-// func (session *Session) GetClients() (clients []Client, err error) {
-// 	dlog.Println("Retrieving clients")
-// 
-// 	data, err := session.get(ClockifyAPI, "/clients", nil)
-// 	if err != nil {
-// 		return clients, err
-// 	}
-// 	err = json.Unmarshal(data, &clients)
-// 	return clients, err
-// }
-// 
-// // CreateClient adds a new client
-// func (session *Session) CreateClient(name string, wid int) (client Client, err error) {
-// 	dlog.Printf("Creating client %s", name)
-// 	data := map[string]interface{}{
-// 		"client": map[string]interface{}{
-// 			"name": name,
-// 			"wid":  wid,
-// 		},
-// 	}
-// 
-// 	respData, err := session.post(ClockifyAPI, "/clients", data)
-// 	if err != nil {
-// 		return client, err
-// 	}
-// 
-// 	var entry struct {
-// 		Data Client `json:"data"`
-// 	}
-// 	err = json.Unmarshal(respData, &entry)
-// 	dlog.Printf("Unmarshaled '%s' into %#v\n", respData, entry)
-// 	if err != nil {
-// 		return client, err
-// 	}
-// 	return entry.Data, nil
-// }
-// 
 // // Copy returns a copy of a TimeEntry.
 // func (e *TimeEntry) Copy() TimeEntry {
 // 	newEntry := *e
@@ -501,49 +654,6 @@ func (session *Session) GetProjects(workspaceID string) (projects []Project, err
 // 	}
 // }
 // 
-// // SetDuration sets a time entry's duration. The duration should be a value in
-// // seconds. The stop time will also be updated. Note that the time entry must
-// // not be running.
-// func (e *TimeEntry) SetDuration(duration int64) error {
-// 	if e.IsRunning() {
-// 		return fmt.Errorf("TimeEntry must be stopped")
-// 	}
-// 
-// 	e.Duration = duration
-// 	newStop := e.Start.Add(time.Duration(duration) * time.Second)
-// 	e.Stop = &newStop
-// 
-// 	return nil
-// }
-// 
-// // SetStartTime sets a time entry's start time. If the time entry is stopped,
-// // the stop time will also be updated.
-// func (e *TimeEntry) SetStartTime(start time.Time, updateEnd bool) {
-// 	e.Start = &start
-// 
-// 	if !e.IsRunning() {
-// 		if updateEnd {
-// 			newStop := start.Add(time.Duration(e.Duration) * time.Second)
-// 			e.Stop = &newStop
-// 		} else {
-// 			e.Duration = e.Stop.Unix() - e.Start.Unix()
-// 		}
-// 	}
-// }
-// 
-// // SetStopTime sets a time entry's stop time. The duration will also be
-// // updated. Note that the time entry must not be running.
-// func (e *TimeEntry) SetStopTime(stop time.Time) (err error) {
-// 	if e.IsRunning() {
-// 		return fmt.Errorf("TimeEntry must be stopped")
-// 	}
-// 
-// 	e.Stop = &stop
-// 	e.Duration = int64(stop.Sub(*e.Start) / time.Second)
-// 
-// 	return nil
-// }
-// 
 // func indexOfTag(tag string, tags []string) int {
 // 	for i, t := range tags {
 // 		if t == tag {
@@ -571,8 +681,56 @@ func (session *Session) GetProjects(workspaceID string) (projects []Project, err
 
 // support /////////////////////////////////////////////////////////////
 
-func (session *Session) request(method string, requestURL string, body io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(method, requestURL, body)
+// request sends a request and returns the whole response body. It is used for
+// endpoints returning a single object, which is small and logged as received
+// by the request* helpers. Lists and reports, whose size depends on the data,
+// are read with stream or decode instead.
+func (session *Session) request(ctx context.Context, method string, requestURL string, body []byte) ([]byte, error) {
+	resp, err := session.do(ctx, method, requestURL, body, "", isIdempotent(method))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// do sends a request, retrying it as the session's retry policy allows, and
+// returns the successful response. The caller must close the response body.
+// Responses with an error status are returned as an *APIError. Requests that
+// are not idempotent, which is decided by the caller since report queries are
+// POSTed, are only retried if the policy allows it.
+func (session *Session) do(ctx context.Context, method string, requestURL string, body []byte, accept string, idempotent bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := session.send(ctx, method, requestURL, body, accept)
+		if err == nil || !session.retry.shouldRetry(ctx, idempotent, attempt, err) {
+			return resp, err
+		}
+
+		wait := session.retry.backoff(attempt, err)
+		dlog.Printf("Retrying %s %s in %v after attempt %d: %v", method, requestURL, wait, attempt, err)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (session *Session) send(ctx context.Context, method string, requestURL string, body []byte, accept string) (*http.Response, error) {
+	if session.limiter != nil {
+		if err := session.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, err
+	}
 
 	if session.APIToken != "" {
 		req.Header.Add("X-Api-Key", session.APIToken)
@@ -580,40 +738,65 @@ func (session *Session) request(method string, requestURL string, body io.Reader
 
 	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if session.userAgent != "" {
+		req.Header.Set("User-Agent", session.userAgent)
 	}
-	defer resp.Body.Close()
 
-	content, err := ioutil.ReadAll(resp.Body)
+	resp, err := session.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		return content, fmt.Errorf(resp.Status)
+		defer resp.Body.Close()
+
+		content, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(req, resp, content)
+	}
+
+	return resp, nil
+}
+
+func (session *Session) client() *http.Client {
+	if session.httpClient != nil {
+		return session.httpClient
 	}
+	return http.DefaultClient
+}
 
-	return content, nil
+func (session *Session) apiURL() string {
+	if session.baseURL != "" {
+		return session.baseURL
+	}
+	return ClockifyAPI
 }
 
-func (session *Session) get(requestURL string, path string, params map[string]string) ([]byte, error) {
+func (session *Session) reportsAPIURL() string {
+	if session.reportsURL != "" {
+		return session.reportsURL
+	}
+	return ClockifyReportsAPI
+}
+
+func (session *Session) get(ctx context.Context, requestURL string, path string, params url.Values) ([]byte, error) {
 	requestURL += path
 
-	if params != nil {
-		data := url.Values{}
-		for key, value := range params {
-			data.Set(key, value)
-		}
-		requestURL += "?" + data.Encode()
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
 	}
 
 	dlog.Printf("GETing from URL: %s", requestURL)
-	return session.request("GET", requestURL, nil)
+	return session.request(ctx, "GET", requestURL, nil)
 }
 
-func (session *Session) post(requestURL string, path string, data interface{}) ([]byte, error) {
+func (session *Session) post(ctx context.Context, requestURL string, path string, data interface{}) ([]byte, error) {
 	requestURL += path
 	var body []byte
 	var err error
@@ -627,10 +810,10 @@ func (session *Session) post(requestURL string, path string, data interface{}) (
 
 	dlog.Printf("POSTing to URL: %s", requestURL)
 	dlog.Printf("data: %s", body)
-	return session.request("POST", requestURL, bytes.NewBuffer(body))
+	return session.request(ctx, "POST", requestURL, body)
 }
 
-func (session *Session) put(requestURL string, path string, data interface{}) ([]byte, error) {
+func (session *Session) put(ctx context.Context, requestURL string, path string, data interface{}) ([]byte, error) {
 	requestURL += path
 	var body []byte
 	var err error
@@ -643,10 +826,10 @@ func (session *Session) put(requestURL string, path string, data interface{}) ([
 	}
 
 	dlog.Printf("PUTing to URL %s: %s", requestURL, string(body))
-	return session.request("PUT", requestURL, bytes.NewBuffer(body))
+	return session.request(ctx, "PUT", requestURL, body)
 }
 
-func (session *Session) patch(requestURL string, path string, data interface{}) ([]byte, error) {
+func (session *Session) patch(ctx context.Context, requestURL string, path string, data interface{}) ([]byte, error) {
 	requestURL += path
 	var body []byte
 	var err error
@@ -659,13 +842,52 @@ func (session *Session) patch(requestURL string, path string, data interface{})
 	}
 
 	dlog.Printf("PATCHing to URL %s: %s", requestURL, string(body))
-	return session.request("PATCH", requestURL, bytes.NewBuffer(body))
+	return session.request(ctx, "PATCH", requestURL, body)
 }
 
-func (session *Session) delete(requestURL string, path string) ([]byte, error) {
+func (session *Session) delete(ctx context.Context, requestURL string, path string) ([]byte, error) {
 	requestURL += path
 	dlog.Printf("DELETINGing URL: %s", requestURL)
-	return session.request("DELETE", requestURL, nil)
+	return session.request(ctx, "DELETE", requestURL, nil)
+}
+
+// stream sends a request and returns the response body unread, so that large
+// responses need not be held in memory. accept, if not empty, is sent as the
+// Accept header. The caller must close the body.
+func (session *Session) stream(ctx context.Context, method string, requestURL string, path string, params url.Values, data interface{}, accept string, idempotent bool) (io.ReadCloser, error) {
+	requestURL += path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	var body []byte
+	var err error
+
+	if data != nil {
+		body, err = json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dlog.Printf("Streaming %s %s: %s", method, requestURL, body)
+	resp, err := session.do(ctx, method, requestURL, body, accept, idempotent)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// decode sends a request and decodes the JSON response body into v as it is
+// read.
+func (session *Session) decode(ctx context.Context, method string, requestURL string, path string, params url.Values, data interface{}, v interface{}, idempotent bool) error {
+	body, err := session.stream(ctx, method, requestURL, path, params, data, "", idempotent)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return json.NewDecoder(body).Decode(v)
 }
 
 func decodeSession(data []byte, session *Session) error {
@@ -686,26 +908,6 @@ func decodeAccount(data []byte, account *Account) error {
 	return nil
 }
 
-// func decodeSummaryReport(data []byte, report *SummaryReport) error {
-// 	dlog.Printf("Decoding %s", data)
-// 	dec := json.NewDecoder(bytes.NewReader(data))
-// 	err := dec.Decode(&report)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
-
-// func decodeDetailedReport(data []byte, report *DetailedReport) error {
-// 	dlog.Printf("Decoding %s", data)
-// 	dec := json.NewDecoder(bytes.NewReader(data))
-// 	err := dec.Decode(&report)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
-
 // This is an alias for TimeEntry that is used in tempTimeEntry to prevent the
 // unmarshaler from infinitely recursing while unmarshaling.
 // type embeddedTimeEntry TimeEntry
@@ -749,6 +951,10 @@ func decodeAccount(data []byte, account *Account) error {
 // 	return
 // }
 
+func decodeTimeEntries(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]TimeEntry{})
+}
+
 func requestTimeEntry(data []byte, err error) (TimeEntry, error) {
 	if err != nil {
 		return TimeEntry{}, err
@@ -765,6 +971,22 @@ func requestTimeEntry(data []byte, err error) (TimeEntry, error) {
 	return entry, nil
 }
 
+func setBoolParam(params url.Values, key string, value *bool) {
+	if value != nil {
+		params.Set(key, strconv.FormatBool(*value))
+	}
+}
+
+// Bool returns a pointer to b, for use in requests with optional fields.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// String returns a pointer to s, for use in requests with optional fields.
+func String(s string) *string {
+	return &s
+}
+
 // DisableLog disables output to stderr
 func DisableLog() {
 	dlog.SetFlags(0)
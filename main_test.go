@@ -0,0 +1,268 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopTimeEntryAt(t *testing.T) {
+	end := time.Date(2020, 5, 10, 17, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("got method %s, want PATCH", r.Method)
+		}
+		if want := "/workspaces/ws1/user/user1/time-entries"; r.URL.Path != want {
+			t.Errorf("got path %s, want %s", r.URL.Path, want)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := body["end"], "2020-05-10T15:30:00Z"; got != want {
+			t.Errorf("got end %v, want %s", got, want)
+		}
+
+		w.Write([]byte(`{"id":"entry1","timeInterval":{"start":"2020-05-10T14:00:00Z","end":"2020-05-10T15:30:00Z","duration":"PT1H30M"}}`))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+	entry, err := session.StopTimeEntryAt("ws1", "user1", end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ID != "entry1" || entry.IsRunning() {
+		t.Errorf("got %+v, want the stopped entry1", entry)
+	}
+}
+
+func TestStopTimeEntryErrors(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message":"failure","code":1}`))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+	if _, err := session.StopTimeEntry("ws1", "user1"); err != ErrNoRunningTimeEntry {
+		t.Errorf("404: got error %v, want ErrNoRunningTimeEntry", err)
+	}
+
+	status = http.StatusUnauthorized
+	_, err := session.StopTimeEntry("ws1", "user1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != status || apiErr.Message != "failure" {
+		t.Errorf("401: got error %v, want an APIError", err)
+	}
+}
+
+func TestTimeEntryIsRunning(t *testing.T) {
+	start, stop := time.Now().Add(-time.Hour), time.Now()
+
+	tests := []struct {
+		name  string
+		entry TimeEntry
+		want  bool
+	}{
+		{"zero", TimeEntry{}, false},
+		{"running", TimeEntry{TimeInterval: TimeInterval{Start: &start}}, true},
+		{"stopped", TimeEntry{TimeInterval: TimeInterval{Start: &start, Stop: &stop}}, false},
+	}
+	for _, test := range tests {
+		if got := test.entry.IsRunning(); got != test.want {
+			t.Errorf("%s: got IsRunning() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRawRejectsForeignURLs(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL+"/api/v1"), WithReportsURL(server.URL+"/reports/v1"))
+
+	for _, path := range []string{
+		"/user",
+		server.URL + "/api/v1/user",
+		server.URL + "/reports/v1/workspaces/ws1/reports/summary",
+	} {
+		body, err := session.Raw("GET", path, nil)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		body.Close()
+	}
+
+	for _, path := range []string{
+		"https://example.com/api/v1/user",
+		server.URL + "/other",
+		server.URL + "/api/v1.example.com/user",
+		server.URL + "/api/v1@example.com/user",
+		"user",
+		"/../other",
+		"/user/../../other",
+	} {
+		if body, err := session.Raw("GET", path, nil); err == nil {
+			body.Close()
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+
+	// Without a path in the base URL, a relative path could otherwise turn
+	// the host into user information.
+	session = NewClient("token", WithBaseURL(server.URL))
+	for _, path := range []string{"@example.com/steal", ":80@example.com/steal", ".example.com/steal"} {
+		if body, err := session.Raw("GET", path, nil); err == nil {
+			body.Close()
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+}
+
+func TestListTimeEntriesSpansPages(t *testing.T) {
+	const total = 120
+	want := map[string][]string{
+		"start":       {"2020-05-01T00:00:00Z"},
+		"end":         {"2020-06-01T00:00:00Z"},
+		"project":     {"project1"},
+		"task":        {"task1"},
+		"tags":        {"tag1", "tag2"},
+		"description": {"meeting"},
+		"in-progress": {"true"},
+		"hydrated":    {"true"},
+	}
+
+	var pages []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want := "/workspaces/ws1/user/user1/time-entries"; r.URL.Path != want {
+			t.Errorf("got path %s, want %s", r.URL.Path, want)
+		}
+		query := r.URL.Query()
+		for key, values := range want {
+			if !reflect.DeepEqual(query[key], values) {
+				t.Errorf("got %s=%v, want %v", key, query[key], values)
+			}
+		}
+
+		page, _ := strconv.Atoi(query.Get("page"))
+		pageSize, _ := strconv.Atoi(query.Get("page-size"))
+		pages = append(pages, page)
+
+		var entries []string
+		for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+			entries = append(entries, fmt.Sprintf(`{"id":"entry%d"}`, i))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(entries, ","))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+	entries, err := session.GetTimeEntries("ws1", "user1", TimeEntryFilter{
+		Start:       time.Date(2020, 5, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+		End:         time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		Project:     "project1",
+		Task:        "task1",
+		Tags:        []string{"tag1", "tag2"},
+		Description: "meeting",
+		InProgress:  true,
+		Hydrated:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != total {
+		t.Fatalf("got %d entries, want %d", len(entries), total)
+	}
+	for i, entry := range entries {
+		if want := fmt.Sprintf("entry%d", i); entry.ID != want {
+			t.Fatalf("got entry %s at %d, want %s", entry.ID, i, want)
+		}
+	}
+	if !reflect.DeepEqual(pages, []int{1, 2, 3}) {
+		t.Errorf("got pages %v, want [1 2 3]", pages)
+	}
+}
+
+func TestTimeEntryUpdate(t *testing.T) {
+	start := time.Date(2020, 5, 10, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	stop := start.Add(time.Hour)
+	entry := TimeEntry{
+		Description:  "Review",
+		Pid:          "project1",
+		Tid:          "task1",
+		Tags:         []string{"tag1"},
+		Billable:     true,
+		TimeInterval: TimeInterval{Start: &start, Stop: &stop},
+	}
+
+	req := NewTimeEntryUpdate(entry).Request()
+	want := UpdateTimeEntryRequest{
+		Start:       "2020-05-10T07:00:00Z",
+		End:         "2020-05-10T08:00:00Z",
+		Billable:    true,
+		Description: "Review",
+		Pid:         "project1",
+		Tid:         "task1",
+		Tags:        []string{"tag1"},
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("got %+v, want %+v", req, want)
+	}
+
+	req = NewTimeEntryUpdate(entry).SetProject("project1").Request()
+	if req.Tid != "task1" {
+		t.Errorf("setting the same project cleared the task")
+	}
+	req = NewTimeEntryUpdate(entry).SetProject("project2").Request()
+	if req.Pid != "project2" || req.Tid != "" {
+		t.Errorf("got project %q and task %q, want project2 without a task", req.Pid, req.Tid)
+	}
+	req = NewTimeEntryUpdate(entry).SetProject("project2").SetTask("task2").Request()
+	if req.Pid != "project2" || req.Tid != "task2" {
+		t.Errorf("got project %q and task %q, want project2 and task2", req.Pid, req.Tid)
+	}
+
+	req = NewTimeEntryUpdate(entry).SetDuration(45 * time.Minute).Request()
+	if req.Start != "2020-05-10T07:00:00Z" || req.End != "2020-05-10T07:45:00Z" {
+		t.Errorf("got %s to %s, want 07:00 to 07:45", req.Start, req.End)
+	}
+
+	newStart := time.Date(2020, 5, 11, 13, 30, 0, 0, time.UTC)
+	req = NewTimeEntryUpdate(entry).SetInterval(newStart, newStart.Add(2*time.Hour)).Request()
+	if req.Start != "2020-05-11T13:30:00Z" || req.End != "2020-05-11T15:30:00Z" {
+		t.Errorf("got %s to %s, want 13:30 to 15:30", req.Start, req.End)
+	}
+}
+
+func TestTimeEntryUpdateWithoutStart(t *testing.T) {
+	update := NewTimeEntryUpdate(TimeEntry{}).SetDuration(30 * time.Minute)
+	if req := update.Request(); req.Start != "" || req.End != "" {
+		t.Errorf("got %q to %q, want no times before the start is known", req.Start, req.End)
+	}
+
+	start := time.Date(2020, 5, 10, 9, 0, 0, 0, time.UTC)
+	if req := update.SetStart(start).Request(); req.Start != "2020-05-10T09:00:00Z" || req.End != "2020-05-10T09:30:00Z" {
+		t.Errorf("got %s to %s, want 09:00 to 09:30", req.Start, req.End)
+	}
+}
@@ -0,0 +1,53 @@
+package clockify
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Session created with NewClient.
+type Option func(*Session)
+
+// WithHTTPClient makes the session send its requests through the given HTTP
+// client instead of a private one.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(session *Session) {
+		if httpClient != nil {
+			session.httpClient = httpClient
+		}
+	}
+}
+
+// WithBaseURL points the session at another Clockify API endpoint, such as a
+// regional or self-hosted instance or a local test server. The URL should
+// include the API version path, e.g. "https://euc1.clockify.me/api/v1".
+func WithBaseURL(baseURL string) Option {
+	return func(session *Session) {
+		session.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithReportsURL points the session at another Clockify reports endpoint, for
+// the same reasons as WithBaseURL. The URL should include the API version
+// path.
+func WithReportsURL(reportsURL string) Option {
+	return func(session *Session) {
+		session.reportsURL = strings.TrimRight(reportsURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(session *Session) {
+		session.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets a time limit for requests made by the session. The HTTP
+// client passed to WithHTTPClient is copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(session *Session) {
+		session.timeout = timeout
+	}
+}
@@ -0,0 +1,273 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrProjectNotFound is returned by FindProjectByName when no project has the
+// requested name.
+var ErrProjectNotFound = errors.New("clockify: project not found")
+
+// AmbiguousProjectError is returned by FindProjectByName when several
+// projects have the requested name.
+type AmbiguousProjectError struct {
+	Name     string
+	Projects []Project
+}
+
+func (e *AmbiguousProjectError) Error() string {
+	return fmt.Sprintf("clockify: %d projects are named %q", len(e.Projects), e.Name)
+}
+
+// Project represents a project.
+type Project struct {
+	Wid            string          `json:"workspaceId"`
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	ClientID       string          `json:"clientId"`
+	ClientName     string          `json:"clientName"`
+	Color          string          `json:"color"`
+	Note           string          `json:"note"`
+	Archived       bool            `json:"archived"`
+	Billable       bool            `json:"billable"`
+	Public         bool            `json:"public"`
+	Template       bool            `json:"template"`
+	HourlyRate     *Rate           `json:"hourlyRate"`
+	CostRate       *Rate           `json:"costRate"`
+	Estimate       *Estimate       `json:"estimate"`
+	BudgetEstimate *BudgetEstimate `json:"budgetEstimate"`
+	Duration       Duration        `json:"duration"`
+	Memberships    []Membership    `json:"memberships"`
+}
+
+// IsActive indicates whether a project exists and is active
+func (p *Project) IsActive() bool {
+	return p.ID != "" && !p.Archived
+}
+
+// BudgetEstimate represents a budget estimate, in cents of the workspace
+// currency.
+type BudgetEstimate struct {
+	Estimate    int    `json:"estimate"`
+	Type        string `json:"type"`
+	ResetOption string `json:"resetOption,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+// ProjectFilter narrows down the projects returned by ListProjects. Zero
+// fields are not used for filtering.
+type ProjectFilter struct {
+	// Name matches projects whose name contains it, or whose name equals it
+	// if StrictNameSearch is set.
+	Name             string
+	StrictNameSearch bool
+	Archived         *bool
+	Billable         *bool
+	// Clients and Users match projects of any of the given client or user
+	// IDs.
+	Clients []string
+	Users   []string
+	// Hydrated makes Clockify return the custom fields of each project.
+	Hydrated bool
+	// SortColumn is one of "NAME", "CLIENT_NAME" or "DURATION".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// PageSize is the number of projects fetched per request.
+	PageSize int
+}
+
+func (filter ProjectFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.StrictNameSearch {
+		params.Set("strict-name-search", "true")
+	}
+	setBoolParam(params, "archived", filter.Archived)
+	setBoolParam(params, "billable", filter.Billable)
+	for _, client := range filter.Clients {
+		params.Add("clients", client)
+	}
+	for _, user := range filter.Users {
+		params.Add("users", user)
+	}
+	if filter.Hydrated {
+		params.Set("hydrated", "true")
+	}
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	return params
+}
+
+// ProjectRequest represents a request creating a project.
+type ProjectRequest struct {
+	Name       string    `json:"name"`
+	ClientID   string    `json:"clientId,omitempty"`
+	Color      string    `json:"color,omitempty"`
+	Billable   bool      `json:"billable"`
+	IsPublic   bool      `json:"isPublic"`
+	Note       string    `json:"note,omitempty"`
+	HourlyRate *Rate     `json:"hourlyRate,omitempty"`
+	Estimate   *Estimate `json:"estimate,omitempty"`
+}
+
+// UpdateProjectRequest represents a request changing a project. Fields left
+// empty or nil are not changed.
+type UpdateProjectRequest struct {
+	Name       string  `json:"name,omitempty"`
+	ClientID   *string `json:"clientId,omitempty"`
+	Color      string  `json:"color,omitempty"`
+	Billable   *bool   `json:"billable,omitempty"`
+	IsPublic   *bool   `json:"isPublic,omitempty"`
+	Archived   *bool   `json:"archived,omitempty"`
+	Note       *string `json:"note,omitempty"`
+	HourlyRate *Rate   `json:"hourlyRate,omitempty"`
+	CostRate   *Rate   `json:"costRate,omitempty"`
+}
+
+// GetProjects allows to query for all projects in a workspace
+func (session *Session) GetProjects(workspaceID string) ([]Project, error) {
+	return session.GetProjectsContext(context.Background(), workspaceID)
+}
+
+// GetProjectsContext is like GetProjects but uses the given context for the
+// requests.
+func (session *Session) GetProjectsContext(ctx context.Context, workspaceID string) ([]Project, error) {
+	dlog.Printf("Getting projects for workspace %s", workspaceID)
+	return session.ListProjectsContext(ctx, workspaceID, ProjectFilter{}).Collect(0)
+}
+
+// ListProjects returns an iterator over the projects in a workspace matching
+// the filter.
+func (session *Session) ListProjects(workspaceID string, filter ProjectFilter) *ProjectIterator {
+	return session.ListProjectsContext(context.Background(), workspaceID, filter)
+}
+
+// ListProjectsContext is like ListProjects but uses the given context for the
+// requests.
+func (session *Session) ListProjectsContext(ctx context.Context, workspaceID string, filter ProjectFilter) *ProjectIterator {
+	path := fmt.Sprintf("/workspaces/%s/projects", workspaceID)
+	return &ProjectIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeProjects)}
+}
+
+// FindProjectByName returns the project of a workspace with the given name,
+// compared case-insensitively. It returns ErrProjectNotFound if there is no
+// such project and an *AmbiguousProjectError if there are several.
+func (session *Session) FindProjectByName(workspaceID, name string) (Project, error) {
+	return session.FindProjectByNameContext(context.Background(), workspaceID, name)
+}
+
+// FindProjectByNameContext is like FindProjectByName but uses the given
+// context for the requests.
+func (session *Session) FindProjectByNameContext(ctx context.Context, workspaceID, name string) (Project, error) {
+	name = strings.TrimSpace(name)
+	candidates, err := session.ListProjectsContext(ctx, workspaceID, ProjectFilter{Name: name}).Collect(0)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var matches []Project
+	for _, project := range candidates {
+		if strings.EqualFold(strings.TrimSpace(project.Name), name) {
+			matches = append(matches, project)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Project{}, ErrProjectNotFound
+	case 1:
+		return matches[0], nil
+	default:
+		return Project{}, &AmbiguousProjectError{Name: name, Projects: matches}
+	}
+}
+
+// CreateProject creates a new project in a workspace.
+func (session *Session) CreateProject(workspaceID string, req ProjectRequest) (Project, error) {
+	return session.CreateProjectContext(context.Background(), workspaceID, req)
+}
+
+// CreateProjectContext is like CreateProject but uses the given context for
+// the request.
+func (session *Session) CreateProjectContext(ctx context.Context, workspaceID string, req ProjectRequest) (Project, error) {
+	dlog.Printf("Creating project %s", req.Name)
+	path := fmt.Sprintf("/workspaces/%s/projects", workspaceID)
+	respData, err := session.post(ctx, session.apiURL(), path, req)
+	return requestProject(respData, err)
+}
+
+// UpdateProject changes information about an existing project.
+func (session *Session) UpdateProject(workspaceID, projectID string, req UpdateProjectRequest) (Project, error) {
+	return session.UpdateProjectContext(context.Background(), workspaceID, projectID, req)
+}
+
+// UpdateProjectContext is like UpdateProject but uses the given context for
+// the request.
+func (session *Session) UpdateProjectContext(ctx context.Context, workspaceID, projectID string, req UpdateProjectRequest) (Project, error) {
+	dlog.Printf("Updating project %v", projectID)
+	path := fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID)
+	respData, err := session.put(ctx, session.apiURL(), path, req)
+	return requestProject(respData, err)
+}
+
+// ArchiveProject archives a project.
+func (session *Session) ArchiveProject(workspaceID, projectID string) (Project, error) {
+	return session.ArchiveProjectContext(context.Background(), workspaceID, projectID)
+}
+
+// ArchiveProjectContext is like ArchiveProject but uses the given context for
+// the request.
+func (session *Session) ArchiveProjectContext(ctx context.Context, workspaceID, projectID string) (Project, error) {
+	return session.UpdateProjectContext(ctx, workspaceID, projectID, UpdateProjectRequest{Archived: Bool(true)})
+}
+
+// DeleteProject deletes a project. Clockify only deletes archived projects,
+// so the project is archived first.
+func (session *Session) DeleteProject(workspaceID, projectID string) (Project, error) {
+	return session.DeleteProjectContext(context.Background(), workspaceID, projectID)
+}
+
+// DeleteProjectContext is like DeleteProject but uses the given context for
+// the requests.
+func (session *Session) DeleteProjectContext(ctx context.Context, workspaceID, projectID string) (Project, error) {
+	if _, err := session.ArchiveProjectContext(ctx, workspaceID, projectID); err != nil {
+		return Project{}, err
+	}
+
+	dlog.Printf("Deleting project %v", projectID)
+	path := fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestProject(respData, err)
+}
+
+func decodeProjects(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]Project{})
+}
+
+func requestProject(data []byte, err error) (Project, error) {
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	err = json.Unmarshal(data, &project)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, project)
+	if err != nil {
+		return Project{}, err
+	}
+
+	return project, nil
+}
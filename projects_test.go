@@ -0,0 +1,140 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeProject(t *testing.T) {
+	tests := []struct {
+		file   string
+		active bool
+		want   Project
+	}{
+		{
+			file:   "project_active.json",
+			active: true,
+			want: Project{
+				Wid:        "5b1667790cb8797321f3d664",
+				ID:         "5b641568b07987035750505e",
+				Name:       "Website redesign",
+				ClientID:   "5b6415e5b07987035750508d",
+				ClientName: "Acme",
+				Color:      "#8bc34a",
+				Note:       "Q3 deliverable",
+				Billable:   true,
+				Public:     true,
+				HourlyRate: &Rate{Amount: 12000, Currency: "EUR"},
+				CostRate:   &Rate{Amount: 6000, Currency: "EUR"},
+				Estimate:   &Estimate{Estimate: Duration(120 * time.Hour), Type: "MANUAL"},
+				BudgetEstimate: &BudgetEstimate{
+					Estimate:    1500000,
+					Type:        "MANUAL",
+					ResetOption: "MONTHLY",
+					Active:      true,
+				},
+				Duration: Duration(52*time.Hour + 15*time.Minute + 30*time.Second),
+				Memberships: []Membership{
+					{
+						UserID:           "5b15a5a4b0798751ad6cd1ef",
+						TargetID:         "5b641568b07987035750505e",
+						MembershipType:   "PROJECT",
+						MembershipStatus: "ACTIVE",
+						HourlyRate:       &Rate{Amount: 15000, Currency: "EUR"},
+					},
+					{
+						UserID:           "5c8a4a8bb079875ea5f4c8c1",
+						TargetID:         "5b641568b07987035750505e",
+						MembershipType:   "PROJECT",
+						MembershipStatus: "PENDING",
+						CostRate:         &Rate{Amount: 4500, Currency: "EUR"},
+					},
+				},
+			},
+		},
+		{
+			file:   "project_archived.json",
+			active: false,
+			want: Project{
+				Wid:         "5b1667790cb8797321f3d664",
+				ID:          "5d2c7e0bb079872b6d9e8a31",
+				Name:        "Legacy support",
+				Color:       "#607d8b",
+				Archived:    true,
+				HourlyRate:  &Rate{Currency: "USD"},
+				Estimate:    &Estimate{Type: "AUTO"},
+				Duration:    Duration(310*time.Hour + 4500*time.Millisecond),
+				Memberships: []Membership{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var project Project
+		if err := json.Unmarshal(data, &project); err != nil {
+			t.Fatalf("%s: %v", test.file, err)
+		}
+		if !reflect.DeepEqual(project, test.want) {
+			t.Errorf("%s: got\n%+v\nwant\n%+v", test.file, project, test.want)
+		}
+		if project.IsActive() != test.active {
+			t.Errorf("%s: got IsActive() = %v, want %v", test.file, project.IsActive(), test.active)
+		}
+	}
+}
+
+func TestFindProjectByName(t *testing.T) {
+	projects := []string{"Website", "Website redesign", "Ops", "OPS ", "Internal"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Clockify matches projects whose name contains the given one.
+		name := strings.ToLower(r.URL.Query().Get("name"))
+		var matches []string
+		for i, project := range projects {
+			if strings.Contains(strings.ToLower(project), name) {
+				matches = append(matches, fmt.Sprintf(`{"id":"project%d","name":%q}`, i, project))
+			}
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(matches, ","))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+
+	project, err := session.FindProjectByName("ws1", " website ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.ID != "project0" {
+		t.Errorf("got %s, want project0", project.ID)
+	}
+
+	for _, name := range []string{"redesign", "Marketing"} {
+		if _, err := session.FindProjectByName("ws1", name); err != ErrProjectNotFound {
+			t.Errorf("%s: got error %v, want ErrProjectNotFound", name, err)
+		}
+	}
+
+	_, err = session.FindProjectByName("ws1", "ops")
+	var ambiguous *AmbiguousProjectError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("got error %v, want an AmbiguousProjectError", err)
+	}
+	if len(ambiguous.Projects) != 2 || ambiguous.Projects[0].ID != "project2" || ambiguous.Projects[1].ID != "project3" {
+		t.Errorf("got ambiguous projects %+v, want project2 and project3", ambiguous.Projects)
+	}
+}
@@ -0,0 +1,106 @@
+package clockify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the number of requests per second Clockify allows for
+// a single API key.
+const DefaultRateLimit = 50
+
+// RateLimiter is a token bucket limiting how fast requests are sent. A single
+// RateLimiter may be shared by several sessions, typically all sessions using
+// the same API key, and is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats reports how much a RateLimiter has slowed requests down.
+type RateLimiterStats struct {
+	// Requests is the number of requests that went through the limiter.
+	Requests int64
+	// Throttled is the number of requests that had to wait.
+	Throttled int64
+	// ThrottledTime is the total time requests spent waiting.
+	ThrottledTime time.Duration
+}
+
+// NewRateLimiter returns a limiter allowing requestsPerSecond requests per
+// second on average, with bursts of up to burst requests. A requestsPerSecond
+// of zero or less disables limiting: Wait never blocks.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimiter makes the session wait on the given limiter before every
+// request, including retries.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(session *Session) {
+		session.limiter = limiter
+	}
+}
+
+// Wait blocks until a request may be sent or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.stats.Requests++
+		l.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	l.stats.Requests++
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		l.stats.Throttled++
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := sleepContext(ctx, wait)
+
+	l.mu.Lock()
+	l.stats.ThrottledTime += time.Since(start)
+	if err != nil {
+		// Give back the token reserved for the abandoned request.
+		l.tokens++
+	}
+	l.mu.Unlock()
+
+	return err
+}
+
+// Stats returns a snapshot of the limiter's counters.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
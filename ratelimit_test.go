@@ -0,0 +1,79 @@
+package clockify
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	// A token every 100ms, after a burst of two.
+	limiter := NewRateLimiter(10, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("three requests took %v, want the third to wait for a token", elapsed)
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 3 || stats.Throttled != 1 {
+		t.Errorf("got %+v, want 3 requests of which 1 throttled", stats)
+	}
+	if stats.ThrottledTime < 80*time.Millisecond {
+		t.Errorf("got throttled time %v, want about 100ms", stats.ThrottledTime)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := NewRateLimiter(10, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("cancelled wait took %v", elapsed)
+	}
+
+	// The cancelled request gave its token back, so the next one only waits
+	// for the token the first request used up.
+	start = time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("wait after cancellation took %v, want at most 100ms", elapsed)
+	}
+
+	if stats := limiter.Stats(); stats.Requests != 3 || stats.Throttled != 2 {
+		t.Errorf("got %+v, want 3 requests of which 2 throttled", stats)
+	}
+}
+
+func TestRateLimiterWithoutRate(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited requests took %v", elapsed)
+	}
+	if stats := limiter.Stats(); stats.Requests != 100 || stats.Throttled != 0 {
+		t.Errorf("got %+v, want 100 unthrottled requests", stats)
+	}
+}
@@ -0,0 +1,692 @@
+package clockify
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// Groupings of report entries.
+const (
+	ReportGroupProject   = "PROJECT"
+	ReportGroupClient    = "CLIENT"
+	ReportGroupTask      = "TASK"
+	ReportGroupTag       = "TAG"
+	ReportGroupUser      = "USER"
+	ReportGroupUserGroup = "USER_GROUP"
+	ReportGroupDate      = "DATE"
+	ReportGroupWeek      = "WEEK"
+	ReportGroupMonth     = "MONTH"
+	ReportGroupTimeEntry = "TIMEENTRY"
+)
+
+// Amounts shown in reports.
+const (
+	AmountEarned = "EARNED"
+	AmountCost   = "COST"
+	AmountProfit = "PROFIT"
+	AmountHidden = "HIDE_AMOUNT"
+)
+
+// Formats of exported reports.
+const (
+	ExportCSV  = "CSV"
+	ExportXLSX = "XLSX"
+	ExportPDF  = "PDF"
+)
+
+// ErrExportTooLarge is returned by ExportDetailedReport when an XLSX or PDF
+// export would leave out some entries of the report.
+var ErrExportTooLarge = errors.New("clockify: report has more entries than a single export holds")
+
+var exportMediaTypes = map[string]string{
+	ExportCSV:  "text/csv",
+	ExportXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	ExportPDF:  "application/pdf",
+}
+
+// ReportFilter holds the date range and filters common to all reports.
+type ReportFilter struct {
+	DateRangeStart time.Time `json:"dateRangeStart"`
+	DateRangeEnd   time.Time `json:"dateRangeEnd"`
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string `json:"sortOrder,omitempty"`
+	// Rounding applies the rounding rules of the workspace to durations.
+	Rounding bool `json:"rounding"`
+	// AmountShown is one of the Amount constants.
+	AmountShown string `json:"amountShown,omitempty"`
+
+	Users              *ReportEntityFilter `json:"users,omitempty"`
+	UserGroups         *ReportEntityFilter `json:"userGroups,omitempty"`
+	Clients            *ReportEntityFilter `json:"clients,omitempty"`
+	Projects           *ReportEntityFilter `json:"projects,omitempty"`
+	Tasks              *ReportEntityFilter `json:"tasks,omitempty"`
+	Tags               *ReportEntityFilter `json:"tags,omitempty"`
+	Billable           *bool               `json:"billable,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	WithoutDescription bool                `json:"withoutDescription,omitempty"`
+	// ApprovalState is "APPROVED", "UNAPPROVED" or "ALL".
+	ApprovalState string              `json:"approvalState,omitempty"`
+	CustomFields  []CustomFieldFilter `json:"customFields,omitempty"`
+}
+
+// ReportEntityFilter restricts a report to entries related to the given
+// users, projects, tags and so on.
+type ReportEntityFilter struct {
+	IDs []string `json:"ids"`
+	// Contains is "CONTAINS", "DOES_NOT_CONTAIN" or, for tags,
+	// "CONTAINS_ONLY".
+	Contains string `json:"contains,omitempty"`
+	// Status is "ACTIVE", "ARCHIVED" or "ALL".
+	Status string `json:"status,omitempty"`
+}
+
+// CustomFieldFilter restricts a report to entries whose custom field has the
+// given value, or no value if Empty is set.
+type CustomFieldFilter struct {
+	ID    string `json:"id"`
+	Value string `json:"value,omitempty"`
+	// Type is the type of the custom field, e.g. "TXT" or "NUMBER".
+	Type  string `json:"type,omitempty"`
+	Empty bool   `json:"empty,omitempty"`
+}
+
+// ReportTotals holds the totals of a report.
+type ReportTotals struct {
+	TotalTime         time.Duration  `json:"totalTime"`
+	TotalBillableTime time.Duration  `json:"totalBillableTime"`
+	EntriesCount      int            `json:"entriesCount"`
+	TotalAmount       float64        `json:"totalAmount"`
+	Amounts           []ReportAmount `json:"amounts"`
+}
+
+// UnmarshalJSON decodes report totals, whose durations are given in seconds.
+func (t *ReportTotals) UnmarshalJSON(b []byte) error {
+	type plainTotals ReportTotals
+	var totals struct {
+		plainTotals
+		TotalTime         float64 `json:"totalTime"`
+		TotalBillableTime float64 `json:"totalBillableTime"`
+	}
+	if err := json.Unmarshal(b, &totals); err != nil {
+		return err
+	}
+
+	*t = ReportTotals(totals.plainTotals)
+	t.TotalTime = seconds(totals.TotalTime)
+	t.TotalBillableTime = seconds(totals.TotalBillableTime)
+	return nil
+}
+
+// MarshalJSON encodes report totals with their durations in seconds, so that
+// they decode back to the same values.
+func (t ReportTotals) MarshalJSON() ([]byte, error) {
+	type plainTotals ReportTotals
+	return json.Marshal(struct {
+		plainTotals
+		TotalTime         float64 `json:"totalTime"`
+		TotalBillableTime float64 `json:"totalBillableTime"`
+	}{plainTotals(t), t.TotalTime.Seconds(), t.TotalBillableTime.Seconds()})
+}
+
+// ReportAmount holds one of the amounts of a report, e.g. the earned amount.
+type ReportAmount struct {
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+// SummaryReportRequest represents a request for a summary report.
+type SummaryReportRequest struct {
+	ReportFilter
+	SummaryFilter SummaryFilter `json:"summaryFilter"`
+}
+
+// SummaryFilter holds the options specific to summary reports.
+type SummaryFilter struct {
+	// Groups lists up to three ReportGroup constants, from the outermost
+	// grouping to the innermost.
+	Groups []string `json:"groups"`
+	// SortColumn is "GROUP", "DURATION" or "AMOUNT".
+	SortColumn string `json:"sortColumn,omitempty"`
+}
+
+// SummaryReport represents a summary report: the totals of the requested date
+// range, and a tree of groups following SummaryFilter.Groups.
+type SummaryReport struct {
+	Totals []ReportTotals `json:"totals"`
+	Groups []SummaryGroup `json:"groupOne"`
+}
+
+// SummaryGroup represents a group of a summary report, such as a project, and
+// the groups nested in it.
+type SummaryGroup struct {
+	ID         string         `json:"_id"`
+	Name       string         `json:"name"`
+	ClientName string         `json:"clientName,omitempty"`
+	Color      string         `json:"color,omitempty"`
+	Duration   time.Duration  `json:"duration"`
+	Amount     float64        `json:"amount"`
+	Children   []SummaryGroup `json:"children,omitempty"`
+}
+
+// UnmarshalJSON decodes a summary group, whose duration is given in seconds.
+func (g *SummaryGroup) UnmarshalJSON(b []byte) error {
+	type plainGroup SummaryGroup
+	var group struct {
+		plainGroup
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(b, &group); err != nil {
+		return err
+	}
+
+	*g = SummaryGroup(group.plainGroup)
+	g.Duration = seconds(group.Duration)
+	return nil
+}
+
+// MarshalJSON encodes a summary group with its duration in seconds.
+func (g SummaryGroup) MarshalJSON() ([]byte, error) {
+	type plainGroup SummaryGroup
+	return json.Marshal(struct {
+		plainGroup
+		Duration float64 `json:"duration"`
+	}{plainGroup(g), g.Duration.Seconds()})
+}
+
+// GetSummaryReport returns a summary report of a workspace.
+func (session *Session) GetSummaryReport(workspaceID string, req SummaryReportRequest) (SummaryReport, error) {
+	return session.GetSummaryReportContext(context.Background(), workspaceID, req)
+}
+
+// GetSummaryReportContext is like GetSummaryReport but uses the given context
+// for the request.
+func (session *Session) GetSummaryReportContext(ctx context.Context, workspaceID string, req SummaryReportRequest) (SummaryReport, error) {
+	dlog.Printf("Getting summary report for workspace %s", workspaceID)
+	path := fmt.Sprintf("/workspaces/%s/reports/summary", workspaceID)
+	body := struct {
+		SummaryReportRequest
+		ExportType string `json:"exportType"`
+	}{req, "JSON"}
+
+	var report SummaryReport
+	if err := session.decode(ctx, "POST", session.reportsAPIURL(), path, nil, body, &report, true); err != nil {
+		return SummaryReport{}, err
+	}
+	return report, nil
+}
+
+// ExportSummaryReport exports a summary report of a workspace as a file in one
+// of the Export formats. The file is streamed from the response, so it can be
+// copied to disk without being held in memory:
+//
+//	file, err := session.ExportSummaryReport(workspaceID, req, clockify.ExportXLSX)
+//	...
+//	defer file.Close()
+//	_, err = io.Copy(out, file)
+//
+// The caller must close the returned reader.
+func (session *Session) ExportSummaryReport(workspaceID string, req SummaryReportRequest, exportType string) (io.ReadCloser, error) {
+	return session.ExportSummaryReportContext(context.Background(), workspaceID, req, exportType)
+}
+
+// ExportSummaryReportContext is like ExportSummaryReport but uses the given
+// context for the request. The context also covers reading the file.
+func (session *Session) ExportSummaryReportContext(ctx context.Context, workspaceID string, req SummaryReportRequest, exportType string) (io.ReadCloser, error) {
+	dlog.Printf("Exporting summary report for workspace %s as %s", workspaceID, exportType)
+	path := fmt.Sprintf("/workspaces/%s/reports/summary", workspaceID)
+	body := struct {
+		SummaryReportRequest
+		ExportType string `json:"exportType"`
+	}{req, exportType}
+	return session.exportReport(ctx, path, body, exportType)
+}
+
+func (session *Session) exportReport(ctx context.Context, path string, body interface{}, exportType string) (io.ReadCloser, error) {
+	mediaType, ok := exportMediaTypes[exportType]
+	if !ok {
+		return nil, fmt.Errorf("clockify: unsupported export type %q", exportType)
+	}
+
+	return session.stream(ctx, "POST", session.reportsAPIURL(), path, nil, body, mediaType, true)
+}
+
+// maxDetailedPageSize is the largest page size of detailed reports.
+const maxDetailedPageSize = 1000
+
+// DetailedReportRequest represents a request for a detailed report.
+type DetailedReportRequest struct {
+	ReportFilter
+	// SortColumn is "DATE", "USER", "DURATION", "DESCRIPTION" or "AMOUNT".
+	SortColumn string `json:"-"`
+	// PageSize is the number of entries fetched per request, at most 1000.
+	PageSize int `json:"-"`
+}
+
+// detailedReportBody is the body sent to fetch a page of a detailed report.
+type detailedReportBody struct {
+	ReportFilter
+	DetailedFilter struct {
+		Page       int    `json:"page"`
+		PageSize   int    `json:"pageSize"`
+		SortColumn string `json:"sortColumn,omitempty"`
+	} `json:"detailedFilter"`
+	ExportType string `json:"exportType"`
+}
+
+// pageSize returns req.PageSize limited to maxDetailedPageSize, or fallback
+// if it is not set. Clockify silently caps larger pages, which would otherwise
+// look like the last page of the report.
+func (req DetailedReportRequest) pageSize(fallback int) int {
+	switch {
+	case req.PageSize <= 0:
+		return fallback
+	case req.PageSize > maxDetailedPageSize:
+		return maxDetailedPageSize
+	}
+	return req.PageSize
+}
+
+func (req DetailedReportRequest) body(page, pageSize int, exportType string) detailedReportBody {
+	body := detailedReportBody{ReportFilter: req.ReportFilter, ExportType: exportType}
+	body.DetailedFilter.Page = page
+	body.DetailedFilter.PageSize = pageSize
+	body.DetailedFilter.SortColumn = req.SortColumn
+	return body
+}
+
+// DetailedTimeEntry represents a time entry of a detailed report, along with
+// the names of the user, project, client, task and tags it relates to.
+type DetailedTimeEntry struct {
+	ID           string              `json:"_id"`
+	Description  string              `json:"description"`
+	UserID       string              `json:"userId"`
+	UserName     string              `json:"userName"`
+	UserEmail    string              `json:"userEmail"`
+	Pid          string              `json:"projectId"`
+	ProjectName  string              `json:"projectName"`
+	ProjectColor string              `json:"projectColor"`
+	ClientID     string              `json:"clientId"`
+	ClientName   string              `json:"clientName"`
+	Tid          string              `json:"taskId"`
+	TaskName     string              `json:"taskName"`
+	TagIDs       []string            `json:"tagIds"`
+	Tags         []ReportTag         `json:"tags"`
+	Billable     bool                `json:"billable"`
+	IsLocked     bool                `json:"isLocked"`
+	ApprovalID   string              `json:"approvalRequestId"`
+	Currency     string              `json:"currency"`
+	Rate         float64             `json:"rate"`
+	Amount       float64             `json:"amount"`
+	CostRate     float64             `json:"costRate"`
+	CostAmount   float64             `json:"costAmount"`
+	TimeInterval ReportTimeInterval  `json:"timeInterval"`
+	CustomFields []ReportCustomField `json:"customFields"`
+}
+
+// ReportTag represents a tag of a detailed report entry.
+type ReportTag struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+// ReportCustomField represents the value of a custom field of a detailed
+// report entry.
+type ReportCustomField struct {
+	ID    string      `json:"customFieldId"`
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ReportTimeInterval represents the time interval of a detailed report entry.
+type ReportTimeInterval struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+}
+
+// UnmarshalJSON decodes a time interval, whose duration is given in seconds.
+func (i *ReportTimeInterval) UnmarshalJSON(b []byte) error {
+	type plainInterval ReportTimeInterval
+	var interval struct {
+		plainInterval
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(b, &interval); err != nil {
+		return err
+	}
+
+	*i = ReportTimeInterval(interval.plainInterval)
+	i.Duration = seconds(interval.Duration)
+	return nil
+}
+
+// MarshalJSON encodes a time interval with its duration in seconds.
+func (i ReportTimeInterval) MarshalJSON() ([]byte, error) {
+	type plainInterval ReportTimeInterval
+	return json.Marshal(struct {
+		plainInterval
+		Duration float64 `json:"duration"`
+	}{plainInterval(i), i.Duration.Seconds()})
+}
+
+// ListDetailedReport returns an iterator over the entries of a detailed
+// report. Pages are fetched as the iterator advances, so that only one page
+// is held in memory at a time.
+func (session *Session) ListDetailedReport(workspaceID string, req DetailedReportRequest) *DetailedTimeEntryIterator {
+	return session.ListDetailedReportContext(context.Background(), workspaceID, req)
+}
+
+// ListDetailedReportContext is like ListDetailedReport but uses the given
+// context for the requests.
+func (session *Session) ListDetailedReportContext(ctx context.Context, workspaceID string, req DetailedReportRequest) *DetailedTimeEntryIterator {
+	path := fmt.Sprintf("/workspaces/%s/reports/detailed", workspaceID)
+	return &DetailedTimeEntryIterator{newIterator(ctx, req.pageSize(defaultPageSize), func(ctx context.Context, page, pageSize int) ([]interface{}, error) {
+		dlog.Printf("Getting page %d of detailed report for workspace %s", page, workspaceID)
+		var report struct {
+			TimeEntries []DetailedTimeEntry `json:"timeentries"`
+		}
+		if err := session.decode(ctx, "POST", session.reportsAPIURL(), path, nil, req.body(page, pageSize, "JSON"), &report, true); err != nil {
+			return nil, err
+		}
+
+		items := make([]interface{}, len(report.TimeEntries))
+		for i := range report.TimeEntries {
+			items[i] = report.TimeEntries[i]
+		}
+		return items, nil
+	})}
+}
+
+// ExportDetailedReport exports a detailed report of a workspace as a file in
+// one of the Export formats, see ExportSummaryReport. Unless req.PageSize is
+// set, the largest page size Clockify accepts is requested.
+//
+// Clockify exports a single page at a time. CSV exports are fetched page by
+// page and joined into one file with a single header line, holding one page
+// in memory at a time. XLSX and PDF files cannot be joined, so the entries of
+// the report are counted first and ErrExportTooLarge is returned if they do
+// not fit in a single page of req.PageSize entries, at most 1000. Narrow down
+// the date range or export as CSV in that case.
+//
+// The caller must close the returned reader.
+func (session *Session) ExportDetailedReport(workspaceID string, req DetailedReportRequest, exportType string) (io.ReadCloser, error) {
+	return session.ExportDetailedReportContext(context.Background(), workspaceID, req, exportType)
+}
+
+// ExportDetailedReportContext is like ExportDetailedReport but uses the given
+// context for the request. The context also covers reading the file.
+func (session *Session) ExportDetailedReportContext(ctx context.Context, workspaceID string, req DetailedReportRequest, exportType string) (io.ReadCloser, error) {
+	dlog.Printf("Exporting detailed report for workspace %s as %s", workspaceID, exportType)
+	path := fmt.Sprintf("/workspaces/%s/reports/detailed", workspaceID)
+	pageSize := req.pageSize(maxDetailedPageSize)
+	if exportType == ExportXLSX || exportType == ExportPDF {
+		count, err := session.countDetailedReport(ctx, path, req)
+		if err != nil {
+			return nil, err
+		}
+		if count > pageSize {
+			return nil, fmt.Errorf("%w: %d entries, at most %d per file", ErrExportTooLarge, count, pageSize)
+		}
+	}
+	if exportType != ExportCSV {
+		return session.exportReport(ctx, path, req.body(1, pageSize, exportType), exportType)
+	}
+
+	export := &detailedCSVExport{
+		ctx:      ctx,
+		session:  session,
+		path:     path,
+		req:      req,
+		pageSize: pageSize,
+	}
+	// The first page is fetched right away so that request errors are
+	// returned here rather than from Read.
+	if err := export.next(); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// countDetailedReport returns the number of entries of a detailed report,
+// fetching a single entry along with the totals.
+func (session *Session) countDetailedReport(ctx context.Context, path string, req DetailedReportRequest) (int, error) {
+	var report struct {
+		Totals []ReportTotals `json:"totals"`
+	}
+	if err := session.decode(ctx, "POST", session.reportsAPIURL(), path, nil, req.body(1, 1, "JSON"), &report, true); err != nil {
+		return 0, err
+	}
+
+	if len(report.Totals) == 0 {
+		return 0, nil
+	}
+	return report.Totals[0].EntriesCount, nil
+}
+
+// detailedCSVExport reads the pages of a detailed report exported as CSV as a
+// single file, leaving out the header line of all pages but the first.
+type detailedCSVExport struct {
+	ctx      context.Context
+	session  *Session
+	path     string
+	req      DetailedReportRequest
+	pageSize int
+
+	page int
+	data *bytes.Reader
+	done bool
+}
+
+func (export *detailedCSVExport) Read(p []byte) (int, error) {
+	for export.data == nil || export.data.Len() == 0 {
+		if export.done {
+			return 0, io.EOF
+		}
+		if err := export.next(); err != nil {
+			return 0, err
+		}
+	}
+	return export.data.Read(p)
+}
+
+// Close releases nothing since the pages are read in full as they are fetched.
+func (export *detailedCSVExport) Close() error {
+	return nil
+}
+
+// next fetches the following page of the report.
+func (export *detailedCSVExport) next() error {
+	export.page++
+	dlog.Printf("Getting page %d of detailed report export", export.page)
+	body, err := export.session.exportReport(export.ctx, export.path, export.req.body(export.page, export.pageSize, ExportCSV), ExportCSV)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	// Records are counted with a CSV reader since quoted fields, such as
+	// descriptions, may span several lines.
+	records := 0
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	for {
+		if _, err := reader.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("clockify: reading page %d of detailed report export: %w", export.page, err)
+		}
+		records++
+	}
+
+	// Every page starts with the header line.
+	export.done = records-1 < export.pageSize
+	if export.page > 1 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		} else {
+			data = nil
+		}
+	}
+	export.data = bytes.NewReader(data)
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// WeeklyReportRequest represents a request for a weekly report. The date range
+// should cover a single week.
+type WeeklyReportRequest struct {
+	ReportFilter
+	WeeklyFilter WeeklyFilter `json:"weeklyFilter"`
+}
+
+// WeeklyFilter holds the options specific to weekly reports.
+type WeeklyFilter struct {
+	// Group is ReportGroupUser or ReportGroupProject, and decides what the
+	// rows of the report are.
+	Group string `json:"group"`
+	// Subgroup is "TIME" or one of the Amount constants, and decides what
+	// the day columns show.
+	Subgroup string `json:"subgroup,omitempty"`
+}
+
+// WeeklyReport represents a weekly report: one row per user or project, with
+// the time tracked on each day of the week.
+type WeeklyReport struct {
+	Totals      []ReportTotals `json:"totals"`
+	TotalsByDay []WeeklyDay    `json:"totalsByDay"`
+	Rows        []WeeklyRow    `json:"groupOne"`
+}
+
+// WeeklyRow represents a row of a weekly report, and the rows nested in it,
+// such as the projects of a user.
+type WeeklyRow struct {
+	ID       string        `json:"_id"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Amount   float64       `json:"amount"`
+	Days     []WeeklyDay   `json:"dayTotals"`
+	Children []WeeklyRow   `json:"children,omitempty"`
+}
+
+// UnmarshalJSON decodes a weekly row, whose duration is given in seconds.
+func (r *WeeklyRow) UnmarshalJSON(b []byte) error {
+	type plainRow WeeklyRow
+	var row struct {
+		plainRow
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+
+	*r = WeeklyRow(row.plainRow)
+	r.Duration = seconds(row.Duration)
+	return nil
+}
+
+// MarshalJSON encodes a weekly row with its duration in seconds.
+func (r WeeklyRow) MarshalJSON() ([]byte, error) {
+	type plainRow WeeklyRow
+	return json.Marshal(struct {
+		plainRow
+		Duration float64 `json:"duration"`
+	}{plainRow(r), r.Duration.Seconds()})
+}
+
+// Week returns the time tracked on each of the seven days starting at start,
+// typically the start of the report's date range. Days are compared by their
+// calendar date, taking the date of start in its own location. Days without
+// entries are zero.
+func (r *WeeklyRow) Week(start time.Time) [7]time.Duration {
+	return weekColumns(r.Days, start)
+}
+
+// Week returns the total time tracked on each of the seven days starting at
+// start, typically the start of the report's date range.
+func (report *WeeklyReport) Week(start time.Time) [7]time.Duration {
+	return weekColumns(report.TotalsByDay, start)
+}
+
+// WeeklyDay holds the time tracked and the amount earned on a day.
+type WeeklyDay struct {
+	Date     time.Time     `json:"date"`
+	Duration time.Duration `json:"duration"`
+	Amount   float64       `json:"amount"`
+}
+
+// UnmarshalJSON decodes a day total, whose duration is given in seconds.
+func (d *WeeklyDay) UnmarshalJSON(b []byte) error {
+	type plainDay WeeklyDay
+	var day struct {
+		plainDay
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(b, &day); err != nil {
+		return err
+	}
+
+	*d = WeeklyDay(day.plainDay)
+	d.Duration = seconds(day.Duration)
+	return nil
+}
+
+// MarshalJSON encodes a day total with its duration in seconds.
+func (d WeeklyDay) MarshalJSON() ([]byte, error) {
+	type plainDay WeeklyDay
+	return json.Marshal(struct {
+		plainDay
+		Duration float64 `json:"duration"`
+	}{plainDay(d), d.Duration.Seconds()})
+}
+
+func weekColumns(days []WeeklyDay, start time.Time) [7]time.Duration {
+	var columns [7]time.Duration
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	for _, day := range days {
+		date := time.Date(day.Date.Year(), day.Date.Month(), day.Date.Day(), 0, 0, 0, 0, time.UTC)
+		i := int(date.Sub(first).Hours() / 24)
+		if i >= 0 && i < len(columns) {
+			columns[i] += day.Duration
+		}
+	}
+	return columns
+}
+
+// GetWeeklyReport returns a weekly report of a workspace.
+func (session *Session) GetWeeklyReport(workspaceID string, req WeeklyReportRequest) (WeeklyReport, error) {
+	return session.GetWeeklyReportContext(context.Background(), workspaceID, req)
+}
+
+// GetWeeklyReportContext is like GetWeeklyReport but uses the given context
+// for the request.
+func (session *Session) GetWeeklyReportContext(ctx context.Context, workspaceID string, req WeeklyReportRequest) (WeeklyReport, error) {
+	dlog.Printf("Getting weekly report for workspace %s", workspaceID)
+	path := fmt.Sprintf("/workspaces/%s/reports/weekly", workspaceID)
+	body := struct {
+		WeeklyReportRequest
+		ExportType string `json:"exportType"`
+	}{req, "JSON"}
+
+	var report WeeklyReport
+	if err := session.decode(ctx, "POST", session.reportsAPIURL(), path, nil, body, &report, true); err != nil {
+		return WeeklyReport{}, err
+	}
+	return report, nil
+}
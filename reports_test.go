@@ -0,0 +1,332 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// detailedReportServer serves a detailed report of the given number of
+// entries, capping pages at maxDetailedPageSize like Clockify does.
+func detailedReportServer(t *testing.T, entries int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body detailedReportBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		page, pageSize := body.DetailedFilter.Page, body.DetailedFilter.PageSize
+		if pageSize > maxDetailedPageSize {
+			pageSize = maxDetailedPageSize
+		}
+
+		var ids []string
+		for i := (page - 1) * pageSize; i < page*pageSize && i < entries; i++ {
+			ids = append(ids, fmt.Sprintf(`{"_id":"entry%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"timeentries":[%s]}`, strings.Join(ids, ","))
+	}))
+}
+
+func TestListDetailedReportClampsPageSize(t *testing.T) {
+	server := detailedReportServer(t, 1500)
+	defer server.Close()
+
+	session := NewClient("token", WithReportsURL(server.URL))
+	entries, err := session.ListDetailedReport("ws1", DetailedReportRequest{PageSize: 5000}).Collect(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1500 {
+		t.Errorf("got %d entries, want 1500", len(entries))
+	}
+}
+
+func TestExportDetailedReportJoinsCSVPages(t *testing.T) {
+	rows := []string{
+		"a,1",
+		"b,2",
+		"\"multi\nline\",3",
+		"d,4",
+		"e,5",
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body detailedReportBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.ExportType != ExportCSV {
+			t.Errorf("got export type %q, want CSV", body.ExportType)
+		}
+		if accept := r.Header.Get("Accept"); accept != "text/csv" {
+			t.Errorf("got Accept %q, want text/csv", accept)
+		}
+
+		page, pageSize := body.DetailedFilter.Page, body.DetailedFilter.PageSize
+		fmt.Fprint(w, "Description,Duration\n")
+		for i := (page - 1) * pageSize; i < page*pageSize && i < len(rows); i++ {
+			fmt.Fprintln(w, rows[i])
+		}
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithReportsURL(server.URL))
+	file, err := session.ExportDetailedReport("ws1", DetailedReportRequest{PageSize: 2}, ExportCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Description,Duration\n" + strings.Join(rows, "\n") + "\n"; string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+}
+
+func TestDecodeSummaryReport(t *testing.T) {
+	data := `{
+		"totals": [{"_id": "", "totalTime": 9000, "totalBillableTime": 5400.5, "entriesCount": 4, "totalAmount": 150.5,
+			"amounts": [{"type": "EARNED", "value": 150.5}]}],
+		"groupOne": [
+			{"_id": "project1", "name": "Website", "clientName": "Acme", "color": "#8bc34a", "duration": 5400, "amount": 150.5,
+				"children": [
+					{"_id": "user1", "name": "Ann", "duration": 3600, "amount": 100,
+						"children": [{"_id": "task1", "name": "Design", "duration": 3600, "amount": 100}]},
+					{"_id": "user2", "name": "Bob", "duration": 1800, "amount": 50.5}
+				]},
+			{"_id": "project2", "name": "Internal", "duration": 3600, "amount": 0}
+		]
+	}`
+
+	var report SummaryReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := SummaryReport{
+		Totals: []ReportTotals{{
+			TotalTime:         150 * time.Minute,
+			TotalBillableTime: 90*time.Minute + 500*time.Millisecond,
+			EntriesCount:      4,
+			TotalAmount:       150.5,
+			Amounts:           []ReportAmount{{Type: AmountEarned, Value: 150.5}},
+		}},
+		Groups: []SummaryGroup{
+			{
+				ID: "project1", Name: "Website", ClientName: "Acme", Color: "#8bc34a",
+				Duration: 90 * time.Minute, Amount: 150.5,
+				Children: []SummaryGroup{
+					{
+						ID: "user1", Name: "Ann", Duration: time.Hour, Amount: 100,
+						Children: []SummaryGroup{{ID: "task1", Name: "Design", Duration: time.Hour, Amount: 100}},
+					},
+					{ID: "user2", Name: "Bob", Duration: 30 * time.Minute, Amount: 50.5},
+				},
+			},
+			{ID: "project2", Name: "Internal", Duration: time.Hour},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got\n%+v\nwant\n%+v", report, want)
+	}
+
+	// Durations are encoded in seconds too, so that reports survive a round
+	// trip through JSON.
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SummaryReport
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("round trip: got\n%+v\nwant\n%+v", decoded, want)
+	}
+}
+
+func TestDecodeDetailedTimeEntry(t *testing.T) {
+	data := `{"_id": "entry1", "description": "Design", "userName": "Ann", "projectName": "Website",
+		"tags": [{"_id": "tag1", "name": "Urgent"}],
+		"timeInterval": {"start": "2020-05-10T09:00:00+02:00", "end": "2020-05-10T10:30:00+02:00", "duration": 5400}}`
+
+	var entry DetailedTimeEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.TimeInterval.Duration != 90*time.Minute {
+		t.Errorf("got duration %v, want 1h30m", entry.TimeInterval.Duration)
+	}
+	if !entry.TimeInterval.End.Equal(time.Date(2020, 5, 10, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("got end %v, want 08:30 UTC", entry.TimeInterval.End)
+	}
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded DetailedTimeEntry
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, entry) {
+		t.Errorf("round trip: got\n%+v\nwant\n%+v", decoded, entry)
+	}
+}
+
+func TestDecodeWeeklyReport(t *testing.T) {
+	data := `{
+		"totals": [{"totalTime": 12600, "totalBillableTime": 0, "entriesCount": 3}],
+		"totalsByDay": [
+			{"date": "2020-05-11T00:00:00Z", "duration": 3600, "amount": 0},
+			{"date": "2020-05-13T00:00:00Z", "duration": 9000, "amount": 0}
+		],
+		"groupOne": [{
+			"_id": "user1", "name": "Ann", "duration": 12600, "amount": 0,
+			"dayTotals": [
+				{"date": "2020-05-11T00:00:00Z", "duration": 3600, "amount": 0},
+				{"date": "2020-05-13T00:00:00Z", "duration": 9000, "amount": 0}
+			],
+			"children": [{
+				"_id": "project1", "name": "Website", "duration": 12600, "amount": 0,
+				"dayTotals": [{"date": "2020-05-13T00:00:00Z", "duration": 9000, "amount": 0}]
+			}]
+		}]
+	}`
+
+	var report WeeklyReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	monday := time.Date(2020, 5, 11, 0, 0, 0, 0, time.UTC)
+	want := [7]time.Duration{time.Hour, 0, 150 * time.Minute, 0, 0, 0, 0}
+	if got := report.Week(monday); got != want {
+		t.Errorf("got totals %v, want %v", got, want)
+	}
+	if got := report.Rows[0].Week(monday); got != want {
+		t.Errorf("got row %v, want %v", got, want)
+	}
+	if got := report.Rows[0].Children[0].Week(monday); got != [7]time.Duration{2: 150 * time.Minute} {
+		t.Errorf("got nested row %v, want 2h30m on Wednesday", got)
+	}
+	if report.Rows[0].Duration != 210*time.Minute || report.Totals[0].TotalTime != 210*time.Minute {
+		t.Errorf("got row duration %v and total %v, want 3h30m", report.Rows[0].Duration, report.Totals[0].TotalTime)
+	}
+
+	encoded, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded WeeklyReport
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, report) {
+		t.Errorf("round trip: got\n%+v\nwant\n%+v", decoded, report)
+	}
+}
+
+func TestWeekColumns(t *testing.T) {
+	day := func(date string, d time.Duration) WeeklyDay {
+		parsed, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return WeeklyDay{Date: parsed, Duration: d}
+	}
+	days := []WeeklyDay{
+		day("2020-05-10T00:00:00Z", time.Minute),
+		day("2020-05-11T00:00:00Z", time.Hour),
+		day("2020-05-11T00:00:00Z", 30*time.Minute),
+		day("2020-05-14T00:00:00+02:00", 2*time.Hour),
+		day("2020-05-17T00:00:00Z", 3*time.Hour),
+		day("2020-05-18T00:00:00Z", 4*time.Hour),
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  [7]time.Duration
+	}{
+		{
+			name:  "UTC",
+			start: time.Date(2020, 5, 11, 0, 0, 0, 0, time.UTC),
+			want:  [7]time.Duration{0: 90 * time.Minute, 3: 2 * time.Hour, 6: 3 * time.Hour},
+		},
+		{
+			// Midnight in Berlin is still the previous day in UTC, but
+			// the week starts on the calendar date of start.
+			name:  "ahead of UTC",
+			start: time.Date(2020, 5, 11, 0, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			want:  [7]time.Duration{0: 90 * time.Minute, 3: 2 * time.Hour, 6: 3 * time.Hour},
+		},
+		{
+			// 20:00 in New York is already the next day in UTC.
+			name:  "behind UTC",
+			start: time.Date(2020, 5, 10, 20, 0, 0, 0, time.FixedZone("EDT", -4*60*60)),
+			want:  [7]time.Duration{0: time.Minute, 1: 90 * time.Minute, 4: 2 * time.Hour},
+		},
+	}
+	for _, test := range tests {
+		if got := weekColumns(days, test.start); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExportDetailedReportRefusesPartialFiles(t *testing.T) {
+	entries := 1500
+	var exports []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body detailedReportBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.ExportType == "JSON" {
+			fmt.Fprintf(w, `{"totals":[{"entriesCount":%d}],"timeentries":[{"_id":"entry0"}]}`, entries)
+			return
+		}
+		exports = append(exports, body.ExportType)
+		w.Write([]byte("file"))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithReportsURL(server.URL))
+	for _, exportType := range []string{ExportXLSX, ExportPDF} {
+		if _, err := session.ExportDetailedReport("ws1", DetailedReportRequest{}, exportType); !errors.Is(err, ErrExportTooLarge) {
+			t.Errorf("%s: got error %v, want ErrExportTooLarge", exportType, err)
+		}
+		if _, err := session.ExportDetailedReport("ws1", DetailedReportRequest{PageSize: 2000}, exportType); !errors.Is(err, ErrExportTooLarge) {
+			t.Errorf("%s with a page size above 1000: got error %v, want ErrExportTooLarge", exportType, err)
+		}
+	}
+	if len(exports) != 0 {
+		t.Errorf("exported %v despite the report being too large", exports)
+	}
+
+	entries = 1000
+	file, err := session.ExportDetailedReport("ws1", DetailedReportRequest{}, ExportXLSX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if data, _ := ioutil.ReadAll(file); string(data) != "file" {
+		t.Errorf("got %q, want the exported file", data)
+	}
+}
@@ -0,0 +1,133 @@
+package clockify
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how a session retries requests that failed with a
+// rate limit (429) or server (5xx) response, or with a network error.
+//
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request,
+	// including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles with every
+	// further attempt, up to MaxBackoff, and is randomized by up to half
+	// its value.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RetryNonIdempotent allows POST and PATCH requests to be retried too.
+	// These are not retried by default since a failed attempt may still
+	// have been applied. Report queries are POSTed but change nothing, so
+	// they are always retried.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is a reasonable policy for batch jobs that may run into
+// Clockify's rate limits.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WithRetryPolicy makes the session retry failed requests according to the
+// given policy. A Retry-After header sent with the response takes precedence
+// over the computed backoff.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(session *Session) {
+		session.retry = policy
+	}
+}
+
+func (policy RetryPolicy) shouldRetry(ctx context.Context, idempotent bool, attempt int, err error) bool {
+	if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+
+	if !policy.RetryNonIdempotent && !idempotent {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			(apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusNotImplemented)
+	}
+
+	// Anything else is a transport error.
+	return true
+}
+
+func (policy RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if wait, ok := parseRetryAfter(apiErr.Header.Get("Retry-After")); ok {
+			return wait
+		}
+	}
+
+	wait := policy.MinBackoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || wait < policy.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+		wait = policy.MaxBackoff
+	}
+
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half))
+	}
+	return wait
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header holding either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
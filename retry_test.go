@@ -0,0 +1,203 @@
+package clockify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	DisableLog()
+}
+
+// failingServer answers the first failures requests with status and the
+// following ones with an empty JSON object. It counts all requests.
+func failingServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func retrySession(server *httptest.Server, policy RetryPolicy) *Session {
+	return NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(policy))
+}
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestRetryRecoversFromFailures(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		server, attempts := failingServer(t, 2, status, "")
+
+		if _, err := retrySession(server, fastRetries).GetAccount(); err != nil {
+			t.Errorf("status %d: unexpected error: %v", status, err)
+		}
+		if *attempts != 3 {
+			t.Errorf("status %d: got %d attempts, want 3", status, *attempts)
+		}
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	server, attempts := failingServer(t, 100, http.StatusServiceUnavailable, "")
+
+	_, err := retrySession(server, fastRetries).GetAccount()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got error %v, want a 503 APIError", err)
+	}
+	if *attempts != 3 {
+		t.Errorf("got %d attempts, want 3", *attempts)
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	server, attempts := failingServer(t, 100, http.StatusBadRequest, "")
+
+	if _, err := retrySession(server, fastRetries).GetAccount(); err == nil {
+		t.Error("expected an error")
+	}
+	if *attempts != 1 {
+		t.Errorf("got %d attempts, want 1", *attempts)
+	}
+}
+
+func TestRetryAfterTakesPrecedence(t *testing.T) {
+	// With an hour of computed backoff, the requests only finish in time if
+	// the Retry-After header is honored.
+	slow := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+
+	for _, retryAfter := range []string{"0", past} {
+		server, attempts := failingServer(t, 1, http.StatusTooManyRequests, retryAfter)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		if _, err := retrySession(server, slow).GetAccountContext(ctx); err != nil {
+			t.Errorf("Retry-After %q: unexpected error: %v", retryAfter, err)
+		}
+		if *attempts != 2 {
+			t.Errorf("Retry-After %q: got %d attempts, want 2", retryAfter, *attempts)
+		}
+		cancel()
+	}
+}
+
+func TestRetryAfterBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	future := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"7", 7 * time.Second, 7 * time.Second},
+		{future, 28 * time.Second, 30 * time.Second},
+	}
+	for _, test := range tests {
+		err := &APIError{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		err.Header.Set("Retry-After", test.retryAfter)
+
+		if wait := policy.backoff(1, err); wait < test.min || wait > test.max {
+			t.Errorf("Retry-After %q: got backoff %v, want between %v and %v", test.retryAfter, wait, test.min, test.max)
+		}
+	}
+}
+
+func TestRetrySkipsNonIdempotentMethods(t *testing.T) {
+	calls := map[string]func(*Session) error{
+		"POST": func(session *Session) error {
+			_, err := session.StartTimeEntry("ws", TimeEntryRequest{})
+			return err
+		},
+		"PATCH": func(session *Session) error {
+			_, err := session.StopTimeEntry("ws", "user")
+			return err
+		},
+	}
+
+	for method, call := range calls {
+		server, attempts := failingServer(t, 100, http.StatusTooManyRequests, "")
+		if err := call(retrySession(server, fastRetries)); !IsRateLimited(err) {
+			t.Errorf("%s: got error %v, want a rate limit error", method, err)
+		}
+		if *attempts != 1 {
+			t.Errorf("%s: got %d attempts, want 1", method, *attempts)
+		}
+
+		policy := fastRetries
+		policy.RetryNonIdempotent = true
+		server, attempts = failingServer(t, 100, http.StatusTooManyRequests, "")
+		call(retrySession(server, policy))
+		if *attempts != 3 {
+			t.Errorf("%s with RetryNonIdempotent: got %d attempts, want 3", method, *attempts)
+		}
+	}
+}
+
+func TestRetryReportQueries(t *testing.T) {
+	calls := map[string]func(*Session) error{
+		"detailed": func(session *Session) error {
+			_, err := session.ListDetailedReport("ws", DetailedReportRequest{}).Collect(0)
+			return err
+		},
+		"summary": func(session *Session) error {
+			_, err := session.GetSummaryReport("ws", SummaryReportRequest{})
+			return err
+		},
+		"weekly": func(session *Session) error {
+			_, err := session.GetWeeklyReport("ws", WeeklyReportRequest{})
+			return err
+		},
+		"export": func(session *Session) error {
+			body, err := session.ExportSummaryReport("ws", SummaryReportRequest{}, ExportCSV)
+			if err == nil {
+				body.Close()
+			}
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		server, attempts := failingServer(t, 2, http.StatusServiceUnavailable, "")
+		session := NewClient("token", WithReportsURL(server.URL), WithRetryPolicy(fastRetries))
+		if err := call(session); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if *attempts != 3 {
+			t.Errorf("%s: got %d attempts, want 3", name, *attempts)
+		}
+	}
+}
+
+func TestRetryBackoffHonorsContext(t *testing.T) {
+	server, attempts := failingServer(t, 100, http.StatusServiceUnavailable, "")
+	slow := RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := retrySession(server, slow).GetAccountContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("backoff was not interrupted, took %v", elapsed)
+	}
+	if *attempts != 1 {
+		t.Errorf("got %d attempts, want 1", *attempts)
+	}
+}
@@ -0,0 +1,205 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ErrTagNotFound is returned by ResolveTags when a tag name is unknown.
+var ErrTagNotFound = errors.New("clockify: tag not found")
+
+// Tag represents a tag.
+type Tag struct {
+	Wid      string `json:"workspaceId"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+// TagFilter narrows down the tags returned by ListTags. Zero fields are not
+// used for filtering.
+type TagFilter struct {
+	// Name matches tags whose name contains it, or whose name equals it if
+	// StrictNameSearch is set.
+	Name             string
+	StrictNameSearch bool
+	Archived         *bool
+	// SortColumn is "NAME".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// PageSize is the number of tags fetched per request.
+	PageSize int
+}
+
+func (filter TagFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.StrictNameSearch {
+		params.Set("strict-name-search", "true")
+	}
+	setBoolParam(params, "archived", filter.Archived)
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	return params
+}
+
+// UpdateTagRequest represents a request changing a tag. Fields left empty or
+// nil are not changed.
+type UpdateTagRequest struct {
+	Name     string `json:"name,omitempty"`
+	Archived *bool  `json:"archived,omitempty"`
+}
+
+// GetTags returns all tags of a workspace matching the filter.
+func (session *Session) GetTags(workspaceID string, filter TagFilter) ([]Tag, error) {
+	return session.GetTagsContext(context.Background(), workspaceID, filter)
+}
+
+// GetTagsContext is like GetTags but uses the given context for the requests.
+func (session *Session) GetTagsContext(ctx context.Context, workspaceID string, filter TagFilter) ([]Tag, error) {
+	dlog.Printf("Getting tags for workspace %s", workspaceID)
+	return session.ListTagsContext(ctx, workspaceID, filter).Collect(0)
+}
+
+// ListTags returns an iterator over the tags of a workspace matching the
+// filter.
+func (session *Session) ListTags(workspaceID string, filter TagFilter) *TagIterator {
+	return session.ListTagsContext(context.Background(), workspaceID, filter)
+}
+
+// ListTagsContext is like ListTags but uses the given context for the
+// requests.
+func (session *Session) ListTagsContext(ctx context.Context, workspaceID string, filter TagFilter) *TagIterator {
+	path := fmt.Sprintf("/workspaces/%s/tags", workspaceID)
+	return &TagIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeTags)}
+}
+
+// CreateTag creates a new tag in a workspace.
+func (session *Session) CreateTag(workspaceID, name string) (Tag, error) {
+	return session.CreateTagContext(context.Background(), workspaceID, name)
+}
+
+// CreateTagContext is like CreateTag but uses the given context for the
+// request.
+func (session *Session) CreateTagContext(ctx context.Context, workspaceID, name string) (Tag, error) {
+	dlog.Printf("Creating tag %s", name)
+	path := fmt.Sprintf("/workspaces/%s/tags", workspaceID)
+	respData, err := session.post(ctx, session.apiURL(), path, map[string]string{"name": name})
+	return requestTag(respData, err)
+}
+
+// UpdateTag changes information about an existing tag.
+func (session *Session) UpdateTag(workspaceID, tagID string, req UpdateTagRequest) (Tag, error) {
+	return session.UpdateTagContext(context.Background(), workspaceID, tagID, req)
+}
+
+// UpdateTagContext is like UpdateTag but uses the given context for the
+// request.
+func (session *Session) UpdateTagContext(ctx context.Context, workspaceID, tagID string, req UpdateTagRequest) (Tag, error) {
+	dlog.Printf("Updating tag %v", tagID)
+	path := fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID)
+	respData, err := session.put(ctx, session.apiURL(), path, req)
+	return requestTag(respData, err)
+}
+
+// ArchiveTag archives a tag.
+func (session *Session) ArchiveTag(workspaceID, tagID string) (Tag, error) {
+	return session.ArchiveTagContext(context.Background(), workspaceID, tagID)
+}
+
+// ArchiveTagContext is like ArchiveTag but uses the given context for the
+// request.
+func (session *Session) ArchiveTagContext(ctx context.Context, workspaceID, tagID string) (Tag, error) {
+	return session.UpdateTagContext(ctx, workspaceID, tagID, UpdateTagRequest{Archived: Bool(true)})
+}
+
+// DeleteTag deletes a tag.
+func (session *Session) DeleteTag(workspaceID, tagID string) (Tag, error) {
+	return session.DeleteTagContext(context.Background(), workspaceID, tagID)
+}
+
+// DeleteTagContext is like DeleteTag but uses the given context for the
+// request.
+func (session *Session) DeleteTagContext(ctx context.Context, workspaceID, tagID string) (Tag, error) {
+	dlog.Printf("Deleting tag %v", tagID)
+	path := fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestTag(respData, err)
+}
+
+// ResolveTags maps tag names, compared case-insensitively, to the tag IDs
+// expected in TimeEntryRequest.Tags. Unknown names are created as new tags if
+// createMissing is set, and reported with ErrTagNotFound otherwise.
+func (session *Session) ResolveTags(workspaceID string, names []string, createMissing bool) ([]string, error) {
+	return session.ResolveTagsContext(context.Background(), workspaceID, names, createMissing)
+}
+
+// ResolveTagsContext is like ResolveTags but uses the given context for the
+// requests.
+func (session *Session) ResolveTagsContext(ctx context.Context, workspaceID string, names []string, createMissing bool) ([]string, error) {
+	tags, err := session.GetTagsContext(ctx, workspaceID, TagFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer active tags over archived ones of the same name.
+	ids := make(map[string]string)
+	for _, tag := range tags {
+		key := strings.ToLower(strings.TrimSpace(tag.Name))
+		if _, ok := ids[key]; !ok || !tag.Archived {
+			ids[key] = tag.ID
+		}
+	}
+
+	tagIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		id, ok := ids[key]
+		if !ok {
+			if !createMissing {
+				return nil, fmt.Errorf("%w: %q", ErrTagNotFound, name)
+			}
+
+			tag, err := session.CreateTagContext(ctx, workspaceID, strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			id = tag.ID
+			ids[key] = id
+		}
+		tagIDs = append(tagIDs, id)
+	}
+
+	return tagIDs, nil
+}
+
+func decodeTags(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]Tag{})
+}
+
+func requestTag(data []byte, err error) (Tag, error) {
+	if err != nil {
+		return Tag{}, err
+	}
+
+	var tag Tag
+	err = json.Unmarshal(data, &tag)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, tag)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return tag, nil
+}
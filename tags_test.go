@@ -0,0 +1,89 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// tagsServer serves a fixed list of tags and creates new ones, recording the
+// names of the created tags.
+func tagsServer(t *testing.T, created *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/workspaces/ws1/tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`[
+				{"id":"old-urgent","name":"Urgent","archived":true},
+				{"id":"urgent","name":"urgent","archived":false},
+				{"id":"meeting","name":"Meeting","archived":false},
+				{"id":"legacy","name":"Legacy","archived":true}
+			]`))
+		case http.MethodPost:
+			var body struct {
+				Name string `json:"name"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			*created = append(*created, body.Name)
+			fmt.Fprintf(w, `{"id":"new-%s","name":%q}`, body.Name, body.Name)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolveTags(t *testing.T) {
+	var created []string
+	session := NewClient("token", WithBaseURL(tagsServer(t, &created).URL))
+
+	ids, err := session.ResolveTags("ws1", []string{"URGENT", " meeting ", "legacy"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"urgent", "meeting", "legacy"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+	if len(created) != 0 {
+		t.Errorf("created tags %v without createMissing", created)
+	}
+}
+
+func TestResolveTagsNotFound(t *testing.T) {
+	var created []string
+	session := NewClient("token", WithBaseURL(tagsServer(t, &created).URL))
+
+	_, err := session.ResolveTags("ws1", []string{"Meeting", "Review"}, false)
+	if !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("got error %v, want ErrTagNotFound", err)
+	}
+	if want := `clockify: tag not found: "Review"`; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestResolveTagsCreatesMissing(t *testing.T) {
+	var created []string
+	session := NewClient("token", WithBaseURL(tagsServer(t, &created).URL))
+
+	ids, err := session.ResolveTags("ws1", []string{"Review", "meeting", " review", "Ops"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"new-Review", "meeting", "new-Review", "new-Ops"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+	if want := []string{"Review", "Ops"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("created %v, want %v", created, want)
+	}
+}
@@ -0,0 +1,174 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Task statuses.
+const (
+	TaskStatusActive = "ACTIVE"
+	TaskStatusDone   = "DONE"
+)
+
+// Task represents a task.
+type Task struct {
+	Pid          string   `json:"projectId"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	AssigneeIDs  []string `json:"assigneeIds"`
+	UserGroupIDs []string `json:"userGroupIds"`
+	Estimate     Duration `json:"estimate"`
+	Duration     Duration `json:"duration"`
+	Status       string   `json:"status"`
+	Billable     bool     `json:"billable"`
+	HourlyRate   *Rate    `json:"hourlyRate"`
+	CostRate     *Rate    `json:"costRate"`
+}
+
+// TaskFilter narrows down the tasks returned by ListTasks. Zero fields are
+// not used for filtering.
+type TaskFilter struct {
+	// Name matches tasks whose name contains it, or whose name equals it if
+	// StrictNameSearch is set.
+	Name             string
+	StrictNameSearch bool
+	// Active matches tasks that are active, or done if set to false.
+	Active *bool
+	// SortColumn is "NAME".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// PageSize is the number of tasks fetched per request.
+	PageSize int
+}
+
+func (filter TaskFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.StrictNameSearch {
+		params.Set("strict-name-search", "true")
+	}
+	setBoolParam(params, "is-active", filter.Active)
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	return params
+}
+
+// TaskRequest represents a request creating or updating a task. Clockify
+// requires the name when updating a task as well.
+type TaskRequest struct {
+	Name         string    `json:"name"`
+	AssigneeIDs  []string  `json:"assigneeIds,omitempty"`
+	UserGroupIDs []string  `json:"userGroupIds,omitempty"`
+	Estimate     *Duration `json:"estimate,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Billable     *bool     `json:"billable,omitempty"`
+}
+
+// GetTasks returns all tasks of a project matching the filter.
+func (session *Session) GetTasks(workspaceID, projectID string, filter TaskFilter) ([]Task, error) {
+	return session.GetTasksContext(context.Background(), workspaceID, projectID, filter)
+}
+
+// GetTasksContext is like GetTasks but uses the given context for the
+// requests.
+func (session *Session) GetTasksContext(ctx context.Context, workspaceID, projectID string, filter TaskFilter) ([]Task, error) {
+	dlog.Printf("Getting tasks for project %s", projectID)
+	return session.ListTasksContext(ctx, workspaceID, projectID, filter).Collect(0)
+}
+
+// ListTasks returns an iterator over the tasks of a project matching the
+// filter.
+func (session *Session) ListTasks(workspaceID, projectID string, filter TaskFilter) *TaskIterator {
+	return session.ListTasksContext(context.Background(), workspaceID, projectID, filter)
+}
+
+// ListTasksContext is like ListTasks but uses the given context for the
+// requests.
+func (session *Session) ListTasksContext(ctx context.Context, workspaceID, projectID string, filter TaskFilter) *TaskIterator {
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", workspaceID, projectID)
+	return &TaskIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeTasks)}
+}
+
+// GetTask returns a task of a project.
+func (session *Session) GetTask(workspaceID, projectID, taskID string) (Task, error) {
+	return session.GetTaskContext(context.Background(), workspaceID, projectID, taskID)
+}
+
+// GetTaskContext is like GetTask but uses the given context for the request.
+func (session *Session) GetTaskContext(ctx context.Context, workspaceID, projectID, taskID string) (Task, error) {
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID)
+	data, err := session.get(ctx, session.apiURL(), path, nil)
+	return requestTask(data, err)
+}
+
+// CreateTask adds a new task to a project.
+func (session *Session) CreateTask(workspaceID, projectID string, req TaskRequest) (Task, error) {
+	return session.CreateTaskContext(context.Background(), workspaceID, projectID, req)
+}
+
+// CreateTaskContext is like CreateTask but uses the given context for the
+// request.
+func (session *Session) CreateTaskContext(ctx context.Context, workspaceID, projectID string, req TaskRequest) (Task, error) {
+	dlog.Printf("Creating task %s", req.Name)
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks", workspaceID, projectID)
+	respData, err := session.post(ctx, session.apiURL(), path, req)
+	return requestTask(respData, err)
+}
+
+// UpdateTask changes information about an existing task.
+func (session *Session) UpdateTask(workspaceID, projectID, taskID string, req TaskRequest) (Task, error) {
+	return session.UpdateTaskContext(context.Background(), workspaceID, projectID, taskID, req)
+}
+
+// UpdateTaskContext is like UpdateTask but uses the given context for the
+// request.
+func (session *Session) UpdateTaskContext(ctx context.Context, workspaceID, projectID, taskID string, req TaskRequest) (Task, error) {
+	dlog.Printf("Updating task %v", taskID)
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID)
+	respData, err := session.put(ctx, session.apiURL(), path, req)
+	return requestTask(respData, err)
+}
+
+// DeleteTask deletes a task.
+func (session *Session) DeleteTask(workspaceID, projectID, taskID string) (Task, error) {
+	return session.DeleteTaskContext(context.Background(), workspaceID, projectID, taskID)
+}
+
+// DeleteTaskContext is like DeleteTask but uses the given context for the
+// request.
+func (session *Session) DeleteTaskContext(ctx context.Context, workspaceID, projectID, taskID string) (Task, error) {
+	dlog.Printf("Deleting task %v", taskID)
+	path := fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestTask(respData, err)
+}
+
+func decodeTasks(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]Task{})
+}
+
+func requestTask(data []byte, err error) (Task, error) {
+	if err != nil {
+		return Task{}, err
+	}
+
+	var task Task
+	err = json.Unmarshal(data, &task)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, task)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return task, nil
+}
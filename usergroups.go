@@ -0,0 +1,158 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// UserGroup represents a group of users in a workspace.
+type UserGroup struct {
+	Wid     string   `json:"workspaceId"`
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	UserIDs []string `json:"userIds"`
+}
+
+// UserGroupFilter narrows down the user groups returned by ListUserGroups.
+// Zero fields are not used for filtering.
+type UserGroupFilter struct {
+	// Name matches groups whose name contains it.
+	Name string
+	// SortColumn is "NAME".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// PageSize is the number of groups fetched per request.
+	PageSize int
+}
+
+func (filter UserGroupFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	return params
+}
+
+// GetUserGroups returns all user groups of a workspace matching the filter.
+func (session *Session) GetUserGroups(workspaceID string, filter UserGroupFilter) ([]UserGroup, error) {
+	return session.GetUserGroupsContext(context.Background(), workspaceID, filter)
+}
+
+// GetUserGroupsContext is like GetUserGroups but uses the given context for
+// the requests.
+func (session *Session) GetUserGroupsContext(ctx context.Context, workspaceID string, filter UserGroupFilter) ([]UserGroup, error) {
+	dlog.Printf("Getting user groups for workspace %s", workspaceID)
+	return session.ListUserGroupsContext(ctx, workspaceID, filter).Collect(0)
+}
+
+// ListUserGroups returns an iterator over the user groups of a workspace
+// matching the filter.
+func (session *Session) ListUserGroups(workspaceID string, filter UserGroupFilter) *UserGroupIterator {
+	return session.ListUserGroupsContext(context.Background(), workspaceID, filter)
+}
+
+// ListUserGroupsContext is like ListUserGroups but uses the given context for
+// the requests.
+func (session *Session) ListUserGroupsContext(ctx context.Context, workspaceID string, filter UserGroupFilter) *UserGroupIterator {
+	path := fmt.Sprintf("/workspaces/%s/user-groups", workspaceID)
+	return &UserGroupIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeUserGroups)}
+}
+
+// CreateUserGroup creates a new user group in a workspace.
+func (session *Session) CreateUserGroup(workspaceID, name string) (UserGroup, error) {
+	return session.CreateUserGroupContext(context.Background(), workspaceID, name)
+}
+
+// CreateUserGroupContext is like CreateUserGroup but uses the given context
+// for the request.
+func (session *Session) CreateUserGroupContext(ctx context.Context, workspaceID, name string) (UserGroup, error) {
+	dlog.Printf("Creating user group %s", name)
+	path := fmt.Sprintf("/workspaces/%s/user-groups", workspaceID)
+	respData, err := session.post(ctx, session.apiURL(), path, map[string]string{"name": name})
+	return requestUserGroup(respData, err)
+}
+
+// RenameUserGroup changes the name of a user group.
+func (session *Session) RenameUserGroup(workspaceID, groupID, name string) (UserGroup, error) {
+	return session.RenameUserGroupContext(context.Background(), workspaceID, groupID, name)
+}
+
+// RenameUserGroupContext is like RenameUserGroup but uses the given context
+// for the request.
+func (session *Session) RenameUserGroupContext(ctx context.Context, workspaceID, groupID, name string) (UserGroup, error) {
+	dlog.Printf("Renaming user group %v to %s", groupID, name)
+	path := fmt.Sprintf("/workspaces/%s/user-groups/%s", workspaceID, groupID)
+	respData, err := session.put(ctx, session.apiURL(), path, map[string]string{"name": name})
+	return requestUserGroup(respData, err)
+}
+
+// DeleteUserGroup deletes a user group. Its users stay in the workspace.
+func (session *Session) DeleteUserGroup(workspaceID, groupID string) (UserGroup, error) {
+	return session.DeleteUserGroupContext(context.Background(), workspaceID, groupID)
+}
+
+// DeleteUserGroupContext is like DeleteUserGroup but uses the given context
+// for the request.
+func (session *Session) DeleteUserGroupContext(ctx context.Context, workspaceID, groupID string) (UserGroup, error) {
+	dlog.Printf("Deleting user group %v", groupID)
+	path := fmt.Sprintf("/workspaces/%s/user-groups/%s", workspaceID, groupID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestUserGroup(respData, err)
+}
+
+// AddUserToGroup adds a workspace user to a user group.
+func (session *Session) AddUserToGroup(workspaceID, groupID, userID string) (UserGroup, error) {
+	return session.AddUserToGroupContext(context.Background(), workspaceID, groupID, userID)
+}
+
+// AddUserToGroupContext is like AddUserToGroup but uses the given context for
+// the request.
+func (session *Session) AddUserToGroupContext(ctx context.Context, workspaceID, groupID, userID string) (UserGroup, error) {
+	dlog.Printf("Adding user %s to user group %v", userID, groupID)
+	path := fmt.Sprintf("/workspaces/%s/user-groups/%s/users", workspaceID, groupID)
+	respData, err := session.post(ctx, session.apiURL(), path, map[string]string{"userId": userID})
+	return requestUserGroup(respData, err)
+}
+
+// RemoveUserFromGroup removes a user from a user group.
+func (session *Session) RemoveUserFromGroup(workspaceID, groupID, userID string) (UserGroup, error) {
+	return session.RemoveUserFromGroupContext(context.Background(), workspaceID, groupID, userID)
+}
+
+// RemoveUserFromGroupContext is like RemoveUserFromGroup but uses the given
+// context for the request.
+func (session *Session) RemoveUserFromGroupContext(ctx context.Context, workspaceID, groupID, userID string) (UserGroup, error) {
+	dlog.Printf("Removing user %s from user group %v", userID, groupID)
+	path := fmt.Sprintf("/workspaces/%s/user-groups/%s/users/%s", workspaceID, groupID, userID)
+	respData, err := session.delete(ctx, session.apiURL(), path)
+	return requestUserGroup(respData, err)
+}
+
+func decodeUserGroups(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]UserGroup{})
+}
+
+func requestUserGroup(data []byte, err error) (UserGroup, error) {
+	if err != nil {
+		return UserGroup{}, err
+	}
+
+	var group UserGroup
+	err = json.Unmarshal(data, &group)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, group)
+	if err != nil {
+		return UserGroup{}, err
+	}
+
+	return group, nil
+}
@@ -0,0 +1,84 @@
+package clockify
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserGroupMutations(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		method, path, body = r.Method, r.URL.Path, string(data)
+		w.Write([]byte(`{"id":"group1","name":"Developers","workspaceId":"ws1","userIds":["user1"]}`))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+
+	tests := []struct {
+		name   string
+		call   func() (UserGroup, error)
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name:   "create",
+			call:   func() (UserGroup, error) { return session.CreateUserGroup("ws1", "Developers") },
+			method: http.MethodPost,
+			path:   "/workspaces/ws1/user-groups",
+			body:   `{"name":"Developers"}`,
+		},
+		{
+			name:   "rename",
+			call:   func() (UserGroup, error) { return session.RenameUserGroup("ws1", "group1", "Developers") },
+			method: http.MethodPut,
+			path:   "/workspaces/ws1/user-groups/group1",
+			body:   `{"name":"Developers"}`,
+		},
+		{
+			name:   "delete",
+			call:   func() (UserGroup, error) { return session.DeleteUserGroup("ws1", "group1") },
+			method: http.MethodDelete,
+			path:   "/workspaces/ws1/user-groups/group1",
+		},
+		{
+			name:   "add user",
+			call:   func() (UserGroup, error) { return session.AddUserToGroup("ws1", "group1", "user1") },
+			method: http.MethodPost,
+			path:   "/workspaces/ws1/user-groups/group1/users",
+			body:   `{"userId":"user1"}`,
+		},
+		{
+			name:   "remove user",
+			call:   func() (UserGroup, error) { return session.RemoveUserFromGroup("ws1", "group1", "user1") },
+			method: http.MethodDelete,
+			path:   "/workspaces/ws1/user-groups/group1/users/user1",
+		},
+	}
+
+	for _, test := range tests {
+		method, path, body = "", "", ""
+
+		group, err := test.call()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if group.ID != "group1" {
+			t.Errorf("%s: got group %+v, want group1", test.name, group)
+		}
+		if method != test.method || path != test.path {
+			t.Errorf("%s: got %s %s, want %s %s", test.name, method, path, test.method, test.path)
+		}
+		if body != test.body {
+			t.Errorf("%s: got body %q, want %q", test.name, body, test.body)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package clockify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Membership statuses of a user in a workspace.
+const (
+	UserStatusActive   = "ACTIVE"
+	UserStatusPending  = "PENDING"
+	UserStatusDeclined = "DECLINED"
+	UserStatusInactive = "INACTIVE"
+)
+
+// Kinds of memberships returned with each user by ListWorkspaceUsers.
+const (
+	MembershipsNone      = "NONE"
+	MembershipsWorkspace = "WORKSPACE"
+	MembershipsProject   = "PROJECT"
+	MembershipsUserGroup = "USERGROUP"
+	MembershipsAll       = "ALL"
+)
+
+// User represents a member of a workspace.
+type User struct {
+	ID               string          `json:"id"`
+	Email            string          `json:"email"`
+	Name             string          `json:"name"`
+	ProfilePicture   string          `json:"profilePicture"`
+	Status           string          `json:"status"`
+	ActiveWorkspace  string          `json:"activeWorkspace"`
+	DefaultWorkspace string          `json:"defaultWorkspace"`
+	Memberships      []Membership    `json:"memberships"`
+	Settings         AccountSettings `json:"settings"`
+}
+
+// UserFilter narrows down the users returned by ListWorkspaceUsers. Zero
+// fields are not used for filtering.
+type UserFilter struct {
+	Email string
+	Name  string
+	// Status is one of the UserStatus constants, or "ALL".
+	Status string
+	// Roles matches users holding any of the given roles, such as
+	// "WORKSPACE_ADMIN" or "TEAM_MANAGER".
+	Roles []string
+	// SortColumn is one of "EMAIL", "NAME" or "HOURLYRATE".
+	SortColumn string
+	// SortOrder is SortAscending or SortDescending.
+	SortOrder string
+	// Memberships is one of the Memberships constants and selects which
+	// memberships are returned with each user. Clockify defaults to
+	// MembershipsNone.
+	Memberships string
+	// PageSize is the number of users fetched per request.
+	PageSize int
+}
+
+func (filter UserFilter) params() url.Values {
+	params := url.Values{}
+	if filter.Email != "" {
+		params.Set("email", filter.Email)
+	}
+	if filter.Name != "" {
+		params.Set("name", filter.Name)
+	}
+	if filter.Status != "" {
+		params.Set("status", filter.Status)
+	}
+	for _, role := range filter.Roles {
+		params.Add("roles", role)
+	}
+	if filter.SortColumn != "" {
+		params.Set("sort-column", filter.SortColumn)
+	}
+	if filter.SortOrder != "" {
+		params.Set("sort-order", filter.SortOrder)
+	}
+	if filter.Memberships != "" {
+		params.Set("memberships", filter.Memberships)
+	}
+	return params
+}
+
+// GetWorkspaceUsers returns all users of a workspace matching the filter.
+func (session *Session) GetWorkspaceUsers(workspaceID string, filter UserFilter) ([]User, error) {
+	return session.GetWorkspaceUsersContext(context.Background(), workspaceID, filter)
+}
+
+// GetWorkspaceUsersContext is like GetWorkspaceUsers but uses the given
+// context for the requests.
+func (session *Session) GetWorkspaceUsersContext(ctx context.Context, workspaceID string, filter UserFilter) ([]User, error) {
+	dlog.Printf("Getting users for workspace %s", workspaceID)
+	return session.ListWorkspaceUsersContext(ctx, workspaceID, filter).Collect(0)
+}
+
+// ListWorkspaceUsers returns an iterator over the users of a workspace
+// matching the filter.
+func (session *Session) ListWorkspaceUsers(workspaceID string, filter UserFilter) *UserIterator {
+	return session.ListWorkspaceUsersContext(context.Background(), workspaceID, filter)
+}
+
+// ListWorkspaceUsersContext is like ListWorkspaceUsers but uses the given
+// context for the requests.
+func (session *Session) ListWorkspaceUsersContext(ctx context.Context, workspaceID string, filter UserFilter) *UserIterator {
+	path := fmt.Sprintf("/workspaces/%s/users", workspaceID)
+	return &UserIterator{session.listPages(ctx, path, filter.params(), filter.PageSize, decodeUsers)}
+}
+
+// AddWorkspaceUser invites a user to a workspace by email and returns the
+// updated workspace.
+func (session *Session) AddWorkspaceUser(workspaceID, email string) (Workspace, error) {
+	return session.AddWorkspaceUserContext(context.Background(), workspaceID, email)
+}
+
+// AddWorkspaceUserContext is like AddWorkspaceUser but uses the given context
+// for the request.
+func (session *Session) AddWorkspaceUserContext(ctx context.Context, workspaceID, email string) (Workspace, error) {
+	dlog.Printf("Adding user %s to workspace %s", email, workspaceID)
+	path := fmt.Sprintf("/workspaces/%s/users", workspaceID)
+	respData, err := session.post(ctx, session.apiURL(), path, map[string]string{"email": email})
+	return requestWorkspace(respData, err)
+}
+
+// SetWorkspaceUserStatus changes the membership status of a user in a
+// workspace to UserStatusActive or UserStatusInactive.
+func (session *Session) SetWorkspaceUserStatus(workspaceID, userID, status string) (Workspace, error) {
+	return session.SetWorkspaceUserStatusContext(context.Background(), workspaceID, userID, status)
+}
+
+// SetWorkspaceUserStatusContext is like SetWorkspaceUserStatus but uses the
+// given context for the request.
+func (session *Session) SetWorkspaceUserStatusContext(ctx context.Context, workspaceID, userID, status string) (Workspace, error) {
+	dlog.Printf("Setting status of user %s to %s", userID, status)
+	path := fmt.Sprintf("/workspaces/%s/users/%s", workspaceID, userID)
+	respData, err := session.put(ctx, session.apiURL(), path, map[string]string{"membershipStatus": status})
+	return requestWorkspace(respData, err)
+}
+
+// ActivateWorkspaceUser reactivates a user in a workspace.
+func (session *Session) ActivateWorkspaceUser(workspaceID, userID string) (Workspace, error) {
+	return session.ActivateWorkspaceUserContext(context.Background(), workspaceID, userID)
+}
+
+// ActivateWorkspaceUserContext is like ActivateWorkspaceUser but uses the
+// given context for the request.
+func (session *Session) ActivateWorkspaceUserContext(ctx context.Context, workspaceID, userID string) (Workspace, error) {
+	return session.SetWorkspaceUserStatusContext(ctx, workspaceID, userID, UserStatusActive)
+}
+
+// DeactivateWorkspaceUser deactivates a user in a workspace, freeing their
+// seat.
+func (session *Session) DeactivateWorkspaceUser(workspaceID, userID string) (Workspace, error) {
+	return session.DeactivateWorkspaceUserContext(context.Background(), workspaceID, userID)
+}
+
+// DeactivateWorkspaceUserContext is like DeactivateWorkspaceUser but uses the
+// given context for the request.
+func (session *Session) DeactivateWorkspaceUserContext(ctx context.Context, workspaceID, userID string) (Workspace, error) {
+	return session.SetWorkspaceUserStatusContext(ctx, workspaceID, userID, UserStatusInactive)
+}
+
+// SetWorkspaceUserHourlyRate changes the hourly rate of a user in a
+// workspace. The amount is expressed in cents of the workspace currency.
+func (session *Session) SetWorkspaceUserHourlyRate(workspaceID, userID string, amount int) (Workspace, error) {
+	return session.SetWorkspaceUserHourlyRateContext(context.Background(), workspaceID, userID, amount)
+}
+
+// SetWorkspaceUserHourlyRateContext is like SetWorkspaceUserHourlyRate but
+// uses the given context for the request.
+func (session *Session) SetWorkspaceUserHourlyRateContext(ctx context.Context, workspaceID, userID string, amount int) (Workspace, error) {
+	return session.setWorkspaceUserRate(ctx, workspaceID, userID, "hourly-rate", amount)
+}
+
+// SetWorkspaceUserCostRate changes the cost rate of a user in a workspace.
+// The amount is expressed in cents of the workspace currency.
+func (session *Session) SetWorkspaceUserCostRate(workspaceID, userID string, amount int) (Workspace, error) {
+	return session.SetWorkspaceUserCostRateContext(context.Background(), workspaceID, userID, amount)
+}
+
+// SetWorkspaceUserCostRateContext is like SetWorkspaceUserCostRate but uses
+// the given context for the request.
+func (session *Session) SetWorkspaceUserCostRateContext(ctx context.Context, workspaceID, userID string, amount int) (Workspace, error) {
+	return session.setWorkspaceUserRate(ctx, workspaceID, userID, "cost-rate", amount)
+}
+
+func (session *Session) setWorkspaceUserRate(ctx context.Context, workspaceID, userID, rate string, amount int) (Workspace, error) {
+	dlog.Printf("Setting %s of user %s to %d", rate, userID, amount)
+	path := fmt.Sprintf("/workspaces/%s/users/%s/%s", workspaceID, userID, rate)
+	respData, err := session.put(ctx, session.apiURL(), path, map[string]int{"amount": amount})
+	return requestWorkspace(respData, err)
+}
+
+func decodeUsers(r io.Reader) ([]interface{}, error) {
+	return decodeItems(r, &[]User{})
+}
@@ -0,0 +1,30 @@
+package clockify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListWorkspaceUsersMemberships(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("memberships"); got != MembershipsProject {
+			t.Errorf("got memberships %q, want %q", got, MembershipsProject)
+		}
+		if r.URL.Query().Get("page") != "1" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"user1","memberships":[{"userId":"user1","targetId":"project1","membershipType":"PROJECT","membershipStatus":"ACTIVE"}]}]`))
+	}))
+	defer server.Close()
+
+	session := NewClient("token", WithBaseURL(server.URL))
+	users, err := session.GetWorkspaceUsers("ws1", UserFilter{Memberships: MembershipsProject})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || len(users[0].Memberships) != 1 || users[0].Memberships[0].TargetID != "project1" {
+		t.Errorf("got %+v, want user1 with a membership of project1", users)
+	}
+}
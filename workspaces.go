@@ -0,0 +1,176 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time tracking modes of a workspace.
+const (
+	TimeTrackingModeDefault       = "DEFAULT"
+	TimeTrackingModeStopwatchOnly = "STOPWATCH_ONLY"
+)
+
+// Workspace represents a user workspace.
+type Workspace struct {
+	ID                      string            `json:"id"`
+	Name                    string            `json:"name"`
+	ImageURL                string            `json:"imageUrl"`
+	FeatureSubscriptionType string            `json:"featureSubscriptionType"`
+	HourlyRate              *Rate             `json:"hourlyRate"`
+	Memberships             []Membership      `json:"memberships"`
+	Currencies              []Currency        `json:"currencies"`
+	Settings                WorkspaceSettings `json:"workspaceSettings"`
+}
+
+// DefaultCurrency returns the code of the default currency of the workspace,
+// or the currency of its hourly rate if it has none.
+func (w *Workspace) DefaultCurrency() string {
+	for _, currency := range w.Currencies {
+		if currency.IsDefault {
+			return currency.Code
+		}
+	}
+	if w.HourlyRate != nil {
+		return w.HourlyRate.Currency
+	}
+	return ""
+}
+
+// Currency represents a currency enabled in a workspace.
+type Currency struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// WorkspaceSettings represents the rules and preferences of a workspace.
+type WorkspaceSettings struct {
+	TimeRoundingInReports bool       `json:"timeRoundingInReports"`
+	Round                 Rounding   `json:"round"`
+	TrackTimeDownToSecond bool       `json:"trackTimeDownToSecond"`
+	TimeTrackingMode      string     `json:"timeTrackingMode"`
+	LockTimeEntries       *time.Time `json:"lockTimeEntries"`
+
+	ForceProjects    bool `json:"forceProjects"`
+	ForceTasks       bool `json:"forceTasks"`
+	ForceTags        bool `json:"forceTags"`
+	ForceDescription bool `json:"forceDescription"`
+
+	DefaultBillableProjects  bool   `json:"defaultBillableProjects"`
+	IsProjectPublicByDefault bool   `json:"isProjectPublicByDefault"`
+	ProjectFavorites         bool   `json:"projectFavorites"`
+	ProjectGroupingLabel     string `json:"projectGroupingLabel"`
+
+	OnlyAdminsCreateProject            bool     `json:"onlyAdminsCreateProject"`
+	OnlyAdminsCreateTag                bool     `json:"onlyAdminsCreateTag"`
+	OnlyAdminsCreateTask               bool     `json:"onlyAdminsCreateTask"`
+	OnlyAdminsSeeAllTimeEntries        bool     `json:"onlyAdminsSeeAllTimeEntries"`
+	OnlyAdminsSeeBillableRates         bool     `json:"onlyAdminsSeeBillableRates"`
+	OnlyAdminsSeeDashboard             bool     `json:"onlyAdminsSeeDashboard"`
+	OnlyAdminsSeePublicProjectsEntries bool     `json:"onlyAdminsSeePublicProjectsEntries"`
+	AdminOnlyPages                     []string `json:"adminOnlyPages"`
+
+	CanSeeTimeSheet bool `json:"canSeeTimeSheet"`
+	CanSeeTracker   bool `json:"canSeeTracker"`
+}
+
+// Rounding represents how durations are rounded in reports, e.g. "Round to
+// nearest" 15 minutes.
+type Rounding struct {
+	Round   string `json:"round"`
+	Minutes string `json:"minutes"`
+}
+
+// ValidationError lists the rules of a workspace that a time entry breaks.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "clockify: invalid time entry: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateTimeEntry checks a time entry against the rules of the workspace
+// before it is submitted. It returns a *ValidationError if the entry would be
+// rejected.
+func (settings *WorkspaceSettings) ValidateTimeEntry(req TimeEntryRequest) error {
+	var problems []string
+
+	if settings.ForceProjects && req.Pid == "" {
+		problems = append(problems, "a project is required")
+	}
+	if settings.ForceTasks && req.Tid == "" {
+		problems = append(problems, "a task is required")
+	}
+	if settings.ForceTags && len(req.Tags) == 0 {
+		problems = append(problems, "at least one tag is required")
+	}
+	if settings.ForceDescription && strings.TrimSpace(req.Description) == "" {
+		problems = append(problems, "a description is required")
+	}
+
+	if settings.LockTimeEntries != nil && req.Start != "" {
+		start, err := time.Parse(time.RFC3339, req.Start)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid start time %q", req.Start))
+		} else if start.Before(*settings.LockTimeEntries) {
+			problems = append(problems, fmt.Sprintf("entries before %s are locked", settings.LockTimeEntries.Format(time.RFC3339)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ListWorkspaces returns the workspaces the user belongs to.
+func (session *Session) ListWorkspaces() ([]Workspace, error) {
+	return session.ListWorkspacesContext(context.Background())
+}
+
+// ListWorkspacesContext is like ListWorkspaces but uses the given context for
+// the request.
+func (session *Session) ListWorkspacesContext(ctx context.Context) ([]Workspace, error) {
+	data, err := session.get(ctx, session.apiURL(), "/workspaces", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var workspaces []Workspace
+	err = json.Unmarshal(data, &workspaces)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, workspaces)
+	return workspaces, err
+}
+
+// GetWorkspace returns a workspace with its settings.
+func (session *Session) GetWorkspace(workspaceID string) (Workspace, error) {
+	return session.GetWorkspaceContext(context.Background(), workspaceID)
+}
+
+// GetWorkspaceContext is like GetWorkspace but uses the given context for the
+// request.
+func (session *Session) GetWorkspaceContext(ctx context.Context, workspaceID string) (Workspace, error) {
+	path := fmt.Sprintf("/workspaces/%s", workspaceID)
+	data, err := session.get(ctx, session.apiURL(), path, nil)
+	return requestWorkspace(data, err)
+}
+
+func requestWorkspace(data []byte, err error) (Workspace, error) {
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	var workspace Workspace
+	err = json.Unmarshal(data, &workspace)
+	dlog.Printf("Unmarshaled '%s' into %#v\n", data, workspace)
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return workspace, nil
+}